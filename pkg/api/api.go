@@ -2,14 +2,17 @@ package api
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	neturl "net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"github.com/tuvistavie/securerandom"
 
 	"github.com/sosedoff/pgweb/pkg/bookmarks"
@@ -20,9 +23,18 @@ import (
 	"github.com/sosedoff/pgweb/pkg/metrics"
 	"github.com/sosedoff/pgweb/pkg/queries"
 	"github.com/sosedoff/pgweb/pkg/shared"
+	"github.com/sosedoff/pgweb/pkg/snapshots"
 	"github.com/sosedoff/pgweb/static"
 )
 
+const (
+	// 通知推送流的心跳间隔
+	notificationsKeepAlive = 30 * time.Second
+
+	// 查询任务可返回的最大行数，结果保存在内存中直到任务过期
+	maxQueryJobRows = 1000000
+)
+
 var (
 	// DbClient represents the active database connection in a single-session mode
 	// 代表单会话模式下的客户端
@@ -32,9 +44,17 @@ var (
 	// 管理客户端连接的映射，代表多会话模式下的连接管理
 	DbSessions *SessionManager
 
+	// Jobs manages background jobs such as table maintenance
+	// 管理后台任务
+	Jobs *JobManager
+
 	// QueryStore reads the SQL queries stores in the home directory
 	// 从home目录下读取SQL查询
 	QueryStore *queries.Store
+
+	// Snapshots stores periodic table size snapshots of bookmarked databases
+	// 存储书签数据库的表大小快照
+	Snapshots *snapshots.Store
 )
 
 // DB returns a database connection from the client context
@@ -333,6 +353,7 @@ func Disconnect(c *gin.Context) {
 		badRequest(c, err)
 		return
 	}
+	Jobs.RemoveSession(getSessionId(c.Request))
 
 	// 单session模式，直接设置DBClient为nil
 	DbClient = nil
@@ -500,7 +521,7 @@ func GetTableInfo(c *gin.Context) {
 
 // GetHistory renders a list of recent queries
 func GetHistory(c *gin.Context) {
-	successResponse(c, DB(c).History)
+	successResponse(c, DB(c).HistoryRecords())
 }
 
 // GetConnectionInfo renders information about current connection
@@ -530,12 +551,262 @@ func GetServerSettings(c *gin.Context) {
 	serveResult(c, res, err)
 }
 
+// GetSettings renders server settings grouped by category
+// 按分类获取服务器设置及其来源，支持按分类、来源、是否修改过及是否需要重启过滤
+func GetSettings(c *gin.Context) {
+	opts := client.SettingsOptions{
+		Category: getQueryParam(c, "category"),
+		Source:   getQueryParam(c, "source"),
+	}
+
+	var err error
+	if opts.Changed, err = parseBoolQueryParam(c, "changed", false); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if opts.PendingRestart, err = parseBoolQueryParam(c, "pending_restart", false); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	categories, err := DB(c).Settings(opts)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	successResponse(c, categories)
+}
+
+// CompareSettings renders settings that differ between two sessions or bookmarks
+// 比较两个会话或书签连接的服务器设置，只返回值或来源不同的设置
+func CompareSettings(c *gin.Context) {
+	left, closeLeft, err := settingsCompareClient(c, "left")
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	defer closeLeft()
+
+	right, closeRight, err := settingsCompareClient(c, "right")
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	defer closeRight()
+
+	leftSettings, err := left.ServerSettings()
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	rightSettings, err := right.ServerSettings()
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	diffs, err := client.CompareSettings(leftSettings, rightSettings)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	successResponse(c, gin.H{
+		"left_version":  left.ServerVersionInfo(),
+		"right_version": right.ServerVersionInfo(),
+		"differences":   diffs,
+	})
+}
+
+// settingsCompareClient returns the client of the compared side given by the
+// <side>_bookmark or <side>_session parameter, the current connection is used
+// when neither is set. Bookmark connections are closed by the returned func.
+func settingsCompareClient(c *gin.Context, side string) (*client.Client, func(), error) {
+	noop := func() {}
+
+	if bookmarkID := getQueryParam(c, side+"_bookmark"); bookmarkID != "" {
+		// 单数据库连接模式，不允许连接书签中的其他服务器
+		if command.Opts.LockSession {
+			return nil, noop, errSessionLocked
+		}
+		cl, err := ConnectWithBookmark(bookmarkID)
+		if err != nil {
+			return nil, noop, err
+		}
+		if err := cl.Test(); err != nil {
+			cl.Close()
+			return nil, noop, err
+		}
+		return cl, func() { cl.Close() }, nil
+	}
+
+	cl := DB(c)
+	if sid := getQueryParam(c, side+"_session"); sid != "" {
+		if !command.Opts.Sessions {
+			return nil, noop, errSessionsDisabled
+		}
+		cl = DbSessions.Get(sid)
+	}
+	if cl == nil {
+		return nil, noop, errNotConnected
+	}
+
+	return cl, noop, nil
+}
+
+// SetSetting writes the setting value with ALTER SYSTEM
+// 通过 ALTER SYSTEM 修改设置，需要 --allow-settings-change，只读模式下禁止
+func SetSetting(c *gin.Context) {
+	db := settingsChangeDB(c)
+	if db == nil {
+		return
+	}
+
+	name := c.Param("name")
+	value, ok := c.GetPostForm("value")
+	if !ok {
+		badRequest(c, "value is required")
+		return
+	}
+
+	if err := db.AlterSystemSet(name, value); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_ip": c.ClientIP(),
+		"setting":   name,
+		"value":     value,
+	}).Info("server setting changed with ALTER SYSTEM")
+
+	successResponse(c, gin.H{"name": name, "value": value, "reload_required": true})
+}
+
+// ResetSetting removes the setting from postgresql.auto.conf with ALTER SYSTEM RESET
+// 通过 ALTER SYSTEM RESET 重置设置，需要 --allow-settings-change，只读模式下禁止
+func ResetSetting(c *gin.Context) {
+	db := settingsChangeDB(c)
+	if db == nil {
+		return
+	}
+
+	name := c.Param("name")
+	if err := db.AlterSystemReset(name); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_ip": c.ClientIP(),
+		"setting":   name,
+	}).Info("server setting reset with ALTER SYSTEM")
+
+	successResponse(c, gin.H{"name": name, "reload_required": true})
+}
+
+// ReloadSettings reloads server configuration files with pg_reload_conf()
+// 调用 pg_reload_conf() 重新加载配置，需要 --allow-settings-change，只读模式下禁止
+func ReloadSettings(c *gin.Context) {
+	db := settingsChangeDB(c)
+	if db == nil {
+		return
+	}
+
+	if err := db.ReloadConf(); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithField("client_ip", c.ClientIP()).Info("server configuration reloaded")
+	successResponse(c, gin.H{"reloaded": true})
+}
+
+// settingsChangeDB returns the client when settings change is permitted,
+// otherwise renders the error and returns nil
+func settingsChangeDB(c *gin.Context) *client.Client {
+	if !command.Opts.AllowSettingsChange {
+		errorResponse(c, 403, errSettingsChangeDisabled)
+		return nil
+	}
+
+	db := DB(c)
+	if db.IsReadOnly() {
+		errorResponse(c, 403, errReadOnlyMode)
+		return nil
+	}
+
+	return db
+}
+
 // GetActivity renders a list of running queries
 func GetActivity(c *gin.Context) {
 	res, err := DB(c).Activity()
 	serveResult(c, res, err)
 }
 
+// GetBlockingTree renders the tree of backends blocked by lock waits
+// 获取锁等待的阻塞关系树
+func GetBlockingTree(c *gin.Context) {
+	res, err := DB(c).BlockingTree()
+	serveResult(c, res, err)
+}
+
+// GetReplication renders the replication status of a primary or a standby server
+// 获取主备复制状态及 WAL 延迟
+func GetReplication(c *gin.Context) {
+	res, err := DB(c).Replication()
+	serveResult(c, res, err)
+}
+
+// CancelBackend cancels the running query of a backend
+// 取消后端进程正在执行的查询
+func CancelBackend(c *gin.Context) {
+	signalBackend(c, client.BackendActionCancel)
+}
+
+// TerminateBackend terminates a backend process
+// 终止后端进程
+func TerminateBackend(c *gin.Context) {
+	signalBackend(c, client.BackendActionTerminate)
+}
+
+func signalBackend(c *gin.Context, action string) {
+	if !command.Opts.AllowSignals {
+		errorResponse(c, 403, errSignalsDisabled)
+		return
+	}
+
+	db := DB(c)
+	if db.IsReadOnly() {
+		errorResponse(c, 403, errReadOnlyMode)
+		return
+	}
+
+	pid, err := strconv.Atoi(c.Params.ByName("pid"))
+	if err != nil {
+		badRequest(c, "pid must be a number")
+		return
+	}
+
+	result, err := db.SignalBackend(pid, action)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"pid":       result.PID,
+		"action":    result.Action,
+		"delivered": result.Delivered,
+		"permitted": result.Permitted,
+		"client_ip": c.ClientIP(),
+	}).Info("backend signal requested")
+
+	successResponse(c, result)
+}
+
 // GetTableIndexes renders a list of database table indexes
 func GetTableIndexes(c *gin.Context) {
 	res, err := DB(c).TableIndexes(c.Params.ByName("table"))
@@ -564,148 +835,954 @@ func GetTablesStats(c *gin.Context) {
 		return
 	}
 
-	format := getQueryParam(c, "format")
-	if format == "" {
-		format = "json"
-	}
+	serveExportableResult(c, res, "dbstats-"+connCtx.Database)
+}
 
-	// Save as attachment if exporting parameter is set
-	if getQueryParam(c, "export") == "true" {
-		ts := time.Now().Format(time.DateOnly)
+// GetIndexHealth renders the index health report for the database
+// 获取索引健康报告，支持导出
+func GetIndexHealth(c *gin.Context) {
+	db := DB(c)
 
-		filename := fmt.Sprintf("pgweb-dbstats-%s-%s.%s", connCtx.Database, ts, format)
-		c.Writer.Header().Set("Content-disposition", "attachment;filename="+filename)
+	connCtx, err := db.GetConnContext()
+	if err != nil {
+		badRequest(c, err)
+		return
 	}
 
-	switch format {
-	case "json":
-		c.JSON(http.StatusOK, res)
-	case "csv":
-		c.Data(http.StatusOK, "text/csv", res.CSV())
-	case "xml":
-		c.XML(200, res)
-	default:
-		badRequest(c, "invalid format")
+	res, err := db.IndexHealth()
+	if err != nil {
+		badRequest(c, err)
+		return
 	}
+
+	serveExportableResult(c, res, "indexes-"+connCtx.Database)
 }
 
-// HandleQuery runs the database query
-func HandleQuery(query string, c *gin.Context) {
-	metrics.IncrementQueriesCount()
+// GetRoles renders roles with their attributes and memberships
+// 获取角色属性及成员关系，支持导出
+func GetRoles(c *gin.Context) {
+	db := DB(c)
 
-	// 使用 base64 解码字符串
-	rawQuery, err := base64.StdEncoding.DecodeString(desanitize64(query))
-	if err == nil {
-		query = string(rawQuery)
+	connCtx, err := db.GetConnContext()
+	if err != nil {
+		badRequest(c, err)
+		return
 	}
 
-	// 获取指定客户端来执行查询
-	result, err := DB(c).Query(query)
+	res, err := db.Roles()
 	if err != nil {
 		badRequest(c, err)
 		return
 	}
 
-	// 获取 format
-	format := getQueryParam(c, "format")
-	// 获取 filename
-	filename := getQueryParam(c, "filename")
+	serveExportableResult(c, res, "roles-"+connCtx.Database)
+}
 
-	if filename == "" {
-		filename = fmt.Sprintf("pgweb-%v.%v", time.Now().Unix(), format)
+// GetPrivileges renders effective privileges of roles on a table, schema or function
+// 获取指定表、schema 或函数上每个角色的有效权限，支持导出
+func GetPrivileges(c *gin.Context) {
+	objectType := getQueryParam(c, "type")
+	if objectType == "" {
+		objectType = client.PrivilegeObjectTable
 	}
+	object := getQueryParam(c, "object")
 
-	if format != "" {
-		c.Writer.Header().Set("Content-disposition", "attachment;filename="+filename)
+	res, err := DB(c).Privileges(objectType, object)
+	if err != nil {
+		badRequest(c, err)
+		return
 	}
 
-	// 当传递 csv, json, xml 是为下载数据，默认为返回数据
-	switch format {
-	case "csv":
-		c.Data(200, "text/csv", result.CSV())
-	case "json":
-		c.Data(200, "application/json", result.JSON())
-	case "xml":
-		c.XML(200, result)
-	default:
-		c.JSON(200, result)
+	serveExportableResult(c, res, "privileges-"+objectType+"-"+sanitizeFilename(object))
+}
+
+// GetDefaultPrivileges renders default privileges of the database
+// 获取默认权限，支持导出
+func GetDefaultPrivileges(c *gin.Context) {
+	db := DB(c)
+
+	connCtx, err := db.GetConnContext()
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	res, err := db.DefaultPrivileges()
+	if err != nil {
+		badRequest(c, err)
+		return
 	}
+
+	serveExportableResult(c, res, "default-privileges-"+sanitizeFilename(connCtx.Database))
 }
 
-// GetBookmarks renders the list of available bookmarks
-// 获取可用的书签
-func GetBookmarks(c *gin.Context) {
-	manager := bookmarks.NewManager(command.Opts.BookmarksDir)
-	ids, err := manager.ListIDs()
-	serveResult(c, ids, err)
+// GetDependencies renders the dependency tree of a table, column, function or type
+// 获取表、列、函数或类型的依赖树（依赖它的对象或它依赖的对象）
+func GetDependencies(c *gin.Context) {
+	objectType := getQueryParam(c, "type")
+	if objectType == "" {
+		objectType = client.DependencyObjectTable
+	}
+
+	res, err := DB(c).Dependencies(client.DependencyOptions{
+		Type:      objectType,
+		Object:    getQueryParam(c, "object"),
+		Column:    getQueryParam(c, "column"),
+		Direction: getQueryParam(c, "direction"),
+	})
+	serveResult(c, res, err)
 }
 
-// GetInfo renders the pgweb system information
-// 返回系统信息
-func GetInfo(c *gin.Context) {
-	successResponse(c, gin.H{
-		"app": command.Info,
-		"features": gin.H{
-			"session_lock":   command.Opts.LockSession,
-			"query_timeout":  command.Opts.QueryTimeout,
-			"local_queries":  QueryStore != nil,
-			"bookmarks_only": command.Opts.BookmarksOnly,
-		},
+// SearchObjects renders objects with names or comments matching the query
+// 按名称及注释搜索数据库对象，按匹配程度排序
+func SearchObjects(c *gin.Context) {
+	limit, err := parseIntFormValue(c, "limit", 0)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	types := []string{}
+	for _, val := range getQueryParams(c, "type") {
+		types = append(types, strings.Split(val, ",")...)
+	}
+
+	res, err := DB(c).Search(client.SearchOptions{
+		Query: getQueryParam(c, "q"),
+		Types: types,
+		Limit: limit,
 	})
+	serveResult(c, res, err)
 }
 
-// DataExport performs database table export
-// 执行表数据导出
-func DataExport(c *gin.Context) {
-	// 获取客户端
-	db := DB(c)
+// ListenNotifications streams notifications of the channels as Server-Sent Events.
+// The stream ends when the client goes away or the session connection is closed.
+// 订阅 LISTEN/NOTIFY 通道，并以 Server-Sent Events 推送通知
+func ListenNotifications(c *gin.Context) {
+	channels := []string{}
+	for _, val := range getQueryParams(c, "channel") {
+		channels = append(channels, strings.Split(val, ",")...)
+	}
 
-	// 获取客户端信息
-	info, err := db.Info()
+	sub, err := DB(c).Subscribe(channels)
 	if err != nil {
 		badRequest(c, err)
 		return
 	}
+	defer sub.Close()
+
+	logger.WithFields(logrus.Fields{
+		"client_ip": c.ClientIP(),
+		"channels":  sub.Channels(),
+	}).Debug("notifications stream opened")
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("subscribed", gin.H{"channels": sub.Channels()})
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case n, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			c.SSEvent("notification", n)
+			return true
+		case <-time.After(notificationsKeepAlive):
+			// Comment line keeps proxies from closing the idle stream
+			_, err := io.WriteString(w, ": keepalive\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
 
-	// 执行 Dump 命令
-	dump := client.Dump{
-		Table: strings.TrimSpace(c.Request.FormValue("table")),
-	}
+// GetBloat renders estimated bloat of tables and indexes
+// 获取表和索引的膨胀估算，支持排序和导出
+func GetBloat(c *gin.Context) {
+	db := DB(c)
 
-	// Perform validation of pg_dump command availability and compatibility.
-	// Must be done before the actual command is executed to display errors.
-	// 执行 pg_dump 命令校验
-	if err := dump.Validate(db.ServerVersion()); err != nil {
+	connCtx, err := db.GetConnContext()
+	if err != nil {
 		badRequest(c, err)
 		return
 	}
 
-	formattedInfo := info.Format()[0]
-	filename := formattedInfo["current_database"].(string)
-	if dump.Table != "" {
-		filename = filename + "_" + dump.Table
+	res, err := db.Bloat(client.BloatOptions{
+		Method:     getQueryParam(c, "method"),
+		SortColumn: getQueryParam(c, "sort_column"),
+		SortOrder:  getQueryParam(c, "sort_order"),
+	})
+	if err != nil {
+		badRequest(c, err)
+		return
 	}
 
-	filename = sanitizeFilename(filename)
-	filename = fmt.Sprintf("%s_%s", filename, time.Now().Format("20060102_150405"))
+	serveExportableResult(c, res, "bloat-"+connCtx.Database)
+}
 
-	c.Header(
-		"Content-Disposition",
-		fmt.Sprintf(`attachment; filename="%s.sql.gz"`, filename),
-	)
+// GetStatStatements renders top statements collected by pg_stat_statements
+// 获取 pg_stat_statements 统计的 Top 语句，支持导出
+func GetStatStatements(c *gin.Context) {
+	db := DB(c)
 
-	// 执行导出
-	err = dump.Export(c.Request.Context(), db.ConnectionString, c.Writer)
+	connCtx, err := db.GetConnContext()
 	if err != nil {
-		logger.WithError(err).Error("pg_dump command failed")
 		badRequest(c, err)
+		return
 	}
-}
 
-// GetFunction renders function information
-// 获取函数
-func GetFunction(c *gin.Context) {
-	res, err := DB(c).Function(c.Param("id"))
+	limit, err := parseIntFormValue(c, "limit", 0)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	res, err := db.StatStatements(client.StatementsOptions{
+		SortBy: getQueryParam(c, "sort"),
+		Limit:  limit,
+	})
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	serveExportableResult(c, res, "statements-"+connCtx.Database)
+}
+
+// ResetStatStatements discards statistics collected by pg_stat_statements
+// 重置 pg_stat_statements 统计数据
+func ResetStatStatements(c *gin.Context) {
+	if !command.Opts.AllowStatsReset {
+		errorResponse(c, 403, errStatsResetDisabled)
+		return
+	}
+
+	db := DB(c)
+	if db.IsReadOnly() {
+		errorResponse(c, 403, errReadOnlyMode)
+		return
+	}
+
+	if err := db.ResetStatStatements(); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithField("client_ip", c.ClientIP()).Info("pg_stat_statements statistics reset")
+	successResponse(c, gin.H{"reset": true})
+}
+
+// GetMaintenance renders vacuum and analyze status of tables
+// 获取表的 vacuum / analyze 状态及事务 ID 回卷风险，支持导出
+func GetMaintenance(c *gin.Context) {
+	db := DB(c)
+
+	connCtx, err := db.GetConnContext()
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	res, err := db.Maintenance()
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	serveExportableResult(c, res, "maintenance-"+connCtx.Database)
+}
+
+// VacuumTable runs VACUUM (ANALYZE) on the table as a background job
+// 后台执行 VACUUM (ANALYZE)
+func VacuumTable(c *gin.Context) {
+	runTableMaintenance(c, client.MaintenanceVacuum)
+}
+
+// AnalyzeTable runs ANALYZE on the table as a background job
+// 后台执行 ANALYZE
+func AnalyzeTable(c *gin.Context) {
+	runTableMaintenance(c, client.MaintenanceAnalyze)
+}
+
+// ReindexTable runs REINDEX CONCURRENTLY on the table as a background job
+// 后台执行 REINDEX CONCURRENTLY
+func ReindexTable(c *gin.Context) {
+	runTableMaintenance(c, client.MaintenanceReindex)
+}
+
+func runTableMaintenance(c *gin.Context, action string) {
+	db := DB(c)
+	if db.IsReadOnly() {
+		errorResponse(c, 403, errReadOnlyMode)
+		return
+	}
+
+	table := c.Params.ByName("table")
+
+	// Report unsupported actions right away instead of failing the job
+	if _, err := db.MaintenanceCommand(table, action); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	job, err := Jobs.Start(action, getSessionId(c.Request), func(ctx context.Context, _ func(interface{})) (interface{}, error) {
+		return db.RunMaintenance(ctx, table, action)
+	})
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithFields(logrus.Fields{"table": table, "action": action, "job": job.ID}).Info("maintenance job started")
+	successResponse(c, job)
+}
+
+// GetColumnStats renders planner statistics of the table columns
+// 获取表中每列的 pg_stats 统计信息
+func GetColumnStats(c *gin.Context) {
+	res, err := DB(c).ColumnStats(c.Params.ByName("table"))
+	serveResult(c, res, err)
+}
+
+// GetTableProfile renders the cached profile of the table
+// 获取缓存的表数据分析结果
+func GetTableProfile(c *gin.Context) {
+	profile := DB(c).TableProfile(c.Params.ByName("table"))
+	if profile == nil {
+		errorResponse(c, 404, errProfileNotFound)
+		return
+	}
+
+	successResponse(c, profile)
+}
+
+// ProfileTable profiles table columns over a sample as a background job
+// 后台任务分析表中每列的数据（基于 TABLESAMPLE 采样）
+func ProfileTable(c *gin.Context) {
+	db := DB(c)
+	table := c.Params.ByName("table")
+
+	opts := client.ProfileOptions{}
+	if val := c.Request.FormValue("sample"); val != "" {
+		sample, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			badRequest(c, "sample must be a number")
+			return
+		}
+		opts.SamplePercent = sample
+	}
+	if val := c.Request.FormValue("seed"); val != "" {
+		seed, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			badRequest(c, "seed must be a number")
+			return
+		}
+		opts.Seed = seed
+	}
+
+	// Report invalid options right away instead of failing the job
+	if err := opts.Validate(); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	job, err := Jobs.Start("profile", getSessionId(c.Request), func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+		return db.ProfileTable(ctx, table, opts, func(p client.ProfileProgress) {
+			progress(p)
+		})
+	})
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithFields(logrus.Fields{"table": table, "job": job.ID}).Info("profile job started")
+	successResponse(c, job)
+}
+
+// SearchValue searches a value across table columns as a background job
+// 后台任务在所有表的列中搜索指定的值
+func SearchValue(c *gin.Context) {
+	db := DB(c)
+
+	limit, err := parseIntFormValue(c, "limit", 0)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	timeout, err := parseIntFormValue(c, "table_timeout", 0)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	opts := client.ValueSearchOptions{
+		Value:        c.Request.FormValue("value"),
+		Match:        c.Request.FormValue("match"),
+		TableTimeout: time.Duration(timeout) * time.Second,
+		Limit:        limit,
+	}
+	if err := c.Request.ParseForm(); err == nil {
+		for _, schema := range c.Request.Form["schema"] {
+			if schema = strings.TrimSpace(schema); schema != "" {
+				opts.Schemas = append(opts.Schemas, schema)
+			}
+		}
+	}
+	if val := c.Request.FormValue("sample"); val != "" {
+		sample, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			badRequest(c, "sample must be a number")
+			return
+		}
+		opts.SamplePercent = sample
+	}
+
+	// Report invalid options right away instead of failing the job
+	if err := opts.Validate(); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	job, err := Jobs.Start("value_search", getSessionId(c.Request), func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+		return db.SearchValue(ctx, opts, func(p client.ValueSearchProgress) {
+			progress(p)
+		})
+	})
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithFields(logrus.Fields{"schemas": opts.Schemas, "job": job.ID}).Info("value search job started")
+	successResponse(c, job)
+}
+
+// StartQueryJob runs the query as a background job, the result is retrieved
+// later with GetJobResult until the job expires
+// 后台任务执行查询，任务完成后通过 /api/jobs/:id/result 获取或下载结果
+func StartQueryJob(c *gin.Context) {
+	query := decodeQuery(cleanQuery(c.Request.FormValue("query")))
+	if query == "" {
+		badRequest(c, errQueryRequired)
+		return
+	}
+
+	db := DB(c)
+	metrics.IncrementQueriesCount()
+
+	job, err := Jobs.Start(JobKindQuery, getSessionId(c.Request), func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+		start := time.Now()
+		limits := client.StreamLimits{
+			MaxRows:  maxQueryJobRows,
+			MaxBytes: int64(command.Opts.QueryJobsMaxSize) * 1024 * 1024,
+		}
+		result, err := db.StreamQuery(ctx, query, limits, func(rows int) {
+			progress(queryJobProgress{Rows: rows, Elapsed: time.Since(start).Milliseconds()})
+		})
+		if err != nil {
+			return nil, err
+		}
+		return queryJobOutput{result: result}, nil
+	})
+	if err != nil {
+		if errors.Is(err, errJobLimitReached) {
+			errorResponse(c, 429, err)
+			return
+		}
+		badRequest(c, err)
+		return
+	}
+
+	logger.WithField("job", job.ID).Info("query job started")
+	successResponse(c, job)
+}
+
+// GetJobResult renders or exports the result of a completed query job
+// 获取已完成查询任务的结果，传递 format 时下载结果
+func GetJobResult(c *gin.Context) {
+	job, output, ok := Jobs.Output(c.Params.ByName("id"), getSessionId(c.Request))
+	if !ok {
+		errorResponse(c, 404, errJobNotFound)
+		return
+	}
+
+	queryOutput, isQuery := output.(queryJobOutput)
+	if job.Status != JobCompleted || !isQuery {
+		badRequest(c, errJobResultMissing)
+		return
+	}
+
+	format := getQueryParam(c, "format")
+	if format == "" {
+		c.JSON(200, queryOutput.result)
+		return
+	}
+
+	filename := getQueryParam(c, "filename")
+	if filename == "" {
+		filename = fmt.Sprintf("pgweb-%v", job.CreatedAt.Unix())
+	}
+
+	serveExport(c, queryOutput.result, format, filename)
+}
+
+// GetJobs renders background jobs of the session
+// 获取当前会话的后台任务
+func GetJobs(c *gin.Context) {
+	successResponse(c, Jobs.List(getSessionId(c.Request)))
+}
+
+// GetJob renders the status of a background job
+// 获取后台任务状态
+func GetJob(c *gin.Context) {
+	job, ok := Jobs.Get(c.Params.ByName("id"), getSessionId(c.Request))
+	if !ok {
+		errorResponse(c, 404, errJobNotFound)
+		return
+	}
+
+	successResponse(c, job)
+}
+
+// CancelJob cancels a running background job
+// 取消后台任务
+func CancelJob(c *gin.Context) {
+	if err := Jobs.Cancel(c.Params.ByName("id"), getSessionId(c.Request)); err != nil {
+		if err == errJobNotFound {
+			errorResponse(c, 404, err)
+			return
+		}
+		badRequest(c, err)
+		return
+	}
+
+	job, _ := Jobs.Get(c.Params.ByName("id"), getSessionId(c.Request))
+	successResponse(c, job)
+}
+
+// HandleQuery runs the database query
+func HandleQuery(query string, c *gin.Context) {
+	metrics.IncrementQueriesCount()
+
+	// 使用 base64 解码字符串
+	query = decodeQuery(query)
+
+	// 获取指定客户端来执行查询
+	result, err := DB(c).Query(query)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	// 获取 format
+	format := getQueryParam(c, "format")
+	if format == "" {
+		c.JSON(200, result)
+		return
+	}
+
+	// 当传递 format 时为下载数据，默认为返回数据
+	filename := getQueryParam(c, "filename")
+	if filename == "" {
+		filename = fmt.Sprintf("pgweb-%v", time.Now().Unix())
+	}
+
+	serveExport(c, result, format, filename)
+}
+
+// GetBookmarks renders the list of available bookmarks
+// 获取可用的书签
+func GetBookmarks(c *gin.Context) {
+	manager := bookmarks.NewManager(command.Opts.BookmarksDir)
+	ids, err := manager.ListIDs()
+	serveResult(c, ids, err)
+}
+
+// GetInfo renders the pgweb system information
+// 返回系统信息
+func GetInfo(c *gin.Context) {
+	successResponse(c, gin.H{
+		"app": command.Info,
+		"features": gin.H{
+			"session_lock":    command.Opts.LockSession,
+			"query_timeout":   command.Opts.QueryTimeout,
+			"local_queries":   QueryStore != nil,
+			"bookmarks_only":  command.Opts.BookmarksOnly,
+			"import":          !command.Opts.DisableImport,
+			"signals":         command.Opts.AllowSignals,
+			"stats_reset":     command.Opts.AllowStatsReset,
+			"settings_change": command.Opts.AllowSettingsChange,
+			"snapshots":       Snapshots != nil,
+		},
+	})
+}
+
+// GetSnapshotBookmarks renders bookmarks with stored table size snapshots
+// 获取存在表大小快照的书签
+func GetSnapshotBookmarks(c *gin.Context) {
+	if Snapshots == nil {
+		errorResponse(c, 403, errSnapshotsDisabled)
+		return
+	}
+
+	names, err := Snapshots.Bookmarks()
+	serveResult(c, gin.H{"bookmarks": names}, err)
+}
+
+// GetTableSizeHistory renders sizes of the table in snapshots of the last days
+// 获取表在最近若干天快照中的大小变化
+func GetTableSizeHistory(c *gin.Context) {
+	list, ok := readSnapshots(c)
+	if !ok {
+		return
+	}
+
+	successResponse(c, gin.H{
+		"table":  c.Params.ByName("table"),
+		"points": snapshots.History(list, c.Params.ByName("table")),
+	})
+}
+
+// GetTablesGrowth renders the fastest-growing tables in snapshots of the last days
+// 获取最近若干天快照中增长最快的表
+func GetTablesGrowth(c *gin.Context) {
+	limit, err := parseIntFormValue(c, "limit", 10)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	list, ok := readSnapshots(c)
+	if !ok {
+		return
+	}
+
+	growth := snapshots.Growth(list)
+	if len(growth) > limit {
+		growth = growth[:limit]
+	}
+
+	successResponse(c, gin.H{"snapshots": len(list), "tables": growth})
+}
+
+// readSnapshots returns snapshots of the bookmark taken in the last days,
+// the error is rendered when snapshots could not be read
+func readSnapshots(c *gin.Context) ([]snapshots.Snapshot, bool) {
+	if Snapshots == nil {
+		errorResponse(c, 403, errSnapshotsDisabled)
+		return nil, false
+	}
+
+	days, err := parseIntFormValue(c, "days", 30)
+	if err != nil {
+		badRequest(c, err)
+		return nil, false
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	list, err := Snapshots.Read(c.Params.ByName("bookmark"), since)
+	if err != nil {
+		badRequest(c, err)
+		return nil, false
+	}
+
+	return list, true
+}
+
+// DataExport performs database table export
+// 执行表数据导出
+func DataExport(c *gin.Context) {
+	// 获取客户端
+	db := DB(c)
+
+	// 获取客户端信息
+	info, err := db.Info()
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	switch getQueryParam(c, "engine") {
+	case "", "pg_dump":
+	case "native":
+		nativeDataExport(c, db, info.Format()[0]["current_database"].(string))
+		return
+	default:
+		badRequest(c, "invalid export engine")
+		return
+	}
+
+	// 执行 Dump 命令
+	dump, err := parseDumpOptions(c)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	// Perform validation of pg_dump command availability and compatibility.
+	// Must be done before the actual command is executed to display errors.
+	// 执行 pg_dump 命令校验
+	if err := dump.Validate(db.ServerVersion()); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	formattedInfo := info.Format()[0]
+	filename := formattedInfo["current_database"].(string)
+	if len(dump.Tables) == 1 {
+		filename = filename + "_" + dump.Tables[0]
+	}
+
+	filename = sanitizeFilename(filename)
+	filename = fmt.Sprintf("%s_%s", filename, time.Now().Format("20060102_150405"))
+
+	c.Header(
+		"Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s.%s"`, filename, dump.Extension()),
+	)
+
+	// 执行导出
+	err = dump.Export(c.Request.Context(), db.ConnectionString, c.Writer)
+	if err != nil {
+		logger.WithError(err).Error("pg_dump command failed")
+		badRequest(c, err)
+	}
+}
+
+// ImportTable loads the uploaded CSV or NDJSON file into the table as a background job,
+// the job progress contains the number of imported rows
+// 后台任务导入表数据，通过 /api/jobs/:id 获取已导入的行数
+func ImportTable(c *gin.Context) {
+	if command.Opts.DisableImport {
+		errorResponse(c, 403, errImportDisabled)
+		return
+	}
+
+	db := DB(c)
+	if db.IsReadOnly() {
+		errorResponse(c, 403, errReadOnlyMode)
+		return
+	}
+
+	input, filename, err := getUploadedFile(c)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	defer input.Close()
+
+	imp, err := parseImportOptions(c, c.Params.ByName("table"), filename)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	// The job outlives the request, so the upload is saved into a temporary file first
+	file, err := spoolUpload(input)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	log := logger.WithField("table", imp.Table)
+
+	job, err := Jobs.Start(JobKindImport, getSessionId(c.Request), func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+		defer removeSpooledUpload(file)
+
+		start := time.Now()
+		result, err := db.Import(ctx, imp, file, func(rows int64) {
+			progress(importJobProgress{Rows: rows, Elapsed: time.Since(start).Milliseconds()})
+		})
+		if err != nil {
+			log.WithError(err).Error("import failed")
+			return nil, err
+		}
+
+		log.WithField("rows", result.RowsCount).WithField("dry_run", result.DryRun).Info("import completed")
+		return result, nil
+	})
+	if err != nil {
+		removeSpooledUpload(file)
+		badRequest(c, err)
+		return
+	}
+
+	log.WithField("job", job.ID).Info("import job started")
+	successResponse(c, job)
+}
+
+// nativeDataExport streams table or query data without using pg_dump
+// 不依赖 pg_dump 导出表或查询数据
+func nativeDataExport(c *gin.Context, db *client.Client, database string) {
+	batchSize, err := parseIntFormValue(c, "batch_size", 0)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	dump := client.NativeDump{
+		Table:     strings.TrimSpace(getQueryParam(c, "table")),
+		Target:    strings.TrimSpace(getQueryParam(c, "target")),
+		Format:    getQueryParam(c, "format"),
+		BatchSize: batchSize,
+	}
+	if query := cleanQuery(getQueryParam(c, "query")); query != "" {
+		dump.Query = decodeQuery(query)
+	}
+
+	if err := dump.Validate(); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	filename := database
+	if dump.Table != "" {
+		filename = filename + "_" + dump.Table
+	} else {
+		filename = filename + "_" + dump.Target
+	}
+
+	filename = sanitizeFilename(filename)
+	filename = fmt.Sprintf("%s_%s", filename, time.Now().Format("20060102_150405"))
+
+	c.Header(
+		"Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s.%s"`, filename, dump.Extension()),
+	)
+
+	err = db.NativeExport(c.Request.Context(), &dump, c.Writer)
+	if err != nil {
+		logger.WithError(err).Error("native export failed")
+		badRequest(c, err)
+	}
+}
+
+// DataImport restores an uploaded plain SQL or archive dump into the current database.
+// Output of psql / pg_restore is streamed back to the client as it runs.
+// 执行 dump 文件导入
+func DataImport(c *gin.Context) {
+	if command.Opts.DisableImport {
+		errorResponse(c, 403, errImportDisabled)
+		return
+	}
+
+	if command.Opts.LockSession {
+		badRequest(c, errSessionLocked)
+		return
+	}
+
+	db := DB(c)
+	if db.IsReadOnly() {
+		errorResponse(c, 403, errReadOnlyMode)
+		return
+	}
+
+	upload, _, err := getUploadedFile(c)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	defer upload.Close()
+
+	restore := client.Restore{
+		Format: getQueryParam(c, "format"),
+	}
+	if restore.Clean, err = parseBoolQueryParam(c, "clean", false); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if restore.SingleTransaction, err = parseBoolQueryParam(c, "single_transaction", false); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	input, err := restore.Detect(upload)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	// Must be done before the command output is streamed to display errors.
+	if err := restore.Validate(db.ServerVersion()); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	output := newFlushWriter(c.Writer)
+
+	err = restore.Import(c.Request.Context(), db.ConnectionString, input, output)
+	if err != nil {
+		logger.WithError(err).Error("restore failed")
+		fmt.Fprintf(output, "ERROR: %v\n", err)
+		return
+	}
+
+	fmt.Fprintln(output, "Restore completed")
+}
+
+// GetFunction renders function information
+// 获取函数
+func GetFunction(c *gin.Context) {
+	res, err := DB(c).Function(c.Param("id"))
+	serveResult(c, res, err)
+}
+
+// GetPartitionedTable renders partition key and partitions of a partitioned table
+// 获取分区表的分区键及分区
+func GetPartitionedTable(c *gin.Context) {
+	res, err := DB(c).PartitionedTable(c.Param("id"))
+	serveResult(c, res, err)
+}
+
+// GetForeignTable renders foreign table information
+// 获取外部表
+func GetForeignTable(c *gin.Context) {
+	res, err := DB(c).ForeignTable(c.Param("id"))
+	serveResult(c, res, err)
+}
+
+// GetTrigger renders trigger definition
+// 获取触发器
+func GetTrigger(c *gin.Context) {
+	res, err := DB(c).Trigger(c.Param("id"))
+	serveResult(c, res, err)
+}
+
+// GetType renders custom type definition
+// 获取自定义类型
+func GetType(c *gin.Context) {
+	res, err := DB(c).Type(c.Param("id"))
+	serveResult(c, res, err)
+}
+
+// GetExtension renders installed extension information
+// 获取扩展
+func GetExtension(c *gin.Context) {
+	res, err := DB(c).Extension(c.Param("id"))
+	serveResult(c, res, err)
+}
+
+// GetEventTrigger renders event trigger definition
+// 获取事件触发器
+func GetEventTrigger(c *gin.Context) {
+	res, err := DB(c).EventTrigger(c.Param("id"))
+	serveResult(c, res, err)
+}
+
+// GetEventTriggers renders list of event triggers
+// 获取事件触发器列表
+func GetEventTriggers(c *gin.Context) {
+	res, err := DB(c).EventTriggers()
 	serveResult(c, res, err)
 }
 
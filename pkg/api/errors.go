@@ -5,12 +5,25 @@ import (
 )
 
 var (
-	errNotConnected         = errors.New("Not connected")
-	errNotPermitted         = errors.New("Not permitted")
-	errInvalidConnString    = errors.New("Invalid connection string")
-	errSessionRequired      = errors.New("Session ID is required")
-	errSessionLocked        = errors.New("Session is locked")
-	errURLRequired          = errors.New("URL parameter is required")
-	errQueryRequired        = errors.New("Query parameter is required")
-	errDatabaseNameRequired = errors.New("Database name is required")
+	errNotConnected           = errors.New("Not connected")
+	errNotPermitted           = errors.New("Not permitted")
+	errInvalidConnString      = errors.New("Invalid connection string")
+	errSessionRequired        = errors.New("Session ID is required")
+	errSessionsDisabled       = errors.New("Sessions are disabled")
+	errSessionLocked          = errors.New("Session is locked")
+	errURLRequired            = errors.New("URL parameter is required")
+	errQueryRequired          = errors.New("Query parameter is required")
+	errDatabaseNameRequired   = errors.New("Database name is required")
+	errReadOnlyMode           = errors.New("Not permitted in read-only mode")
+	errImportDisabled         = errors.New("Data import is disabled")
+	errFileRequired           = errors.New("File is required")
+	errSignalsDisabled        = errors.New("Backend signals are disabled")
+	errStatsResetDisabled     = errors.New("Statistics reset is disabled")
+	errSettingsChangeDisabled = errors.New("Settings change is disabled")
+	errJobLimitReached        = errors.New("Job limit reached")
+	errJobResultMissing       = errors.New("Job has no query result")
+	errJobMemoryLimit         = errors.New("Memory limit of job results reached")
+	errJobNotFound            = errors.New("Job not found")
+	errSnapshotsDisabled      = errors.New("Table size snapshots are disabled")
+	errProfileNotFound        = errors.New("Table profile not found")
 )
@@ -1,16 +1,22 @@
 package api
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"io"
 	"mime"
 	"net/http"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/sosedoff/pgweb/pkg/client"
 	"github.com/sosedoff/pgweb/pkg/shared"
 )
 
@@ -79,6 +85,15 @@ func desanitize64(query string) string {
 	return query
 }
 
+// 尝试使用 base64 解码查询，失败时返回原始查询
+func decodeQuery(query string) string {
+	rawQuery, err := base64.StdEncoding.DecodeString(desanitize64(query))
+	if err == nil {
+		return string(rawQuery)
+	}
+	return query
+}
+
 func sanitizeFilename(str string) string {
 	str = strings.ReplaceAll(str, ".", "_")
 	return regexCleanFilename.ReplaceAllString(str, "")
@@ -105,6 +120,36 @@ func getQueryParam(c *gin.Context, name string) string {
 	return result
 }
 
+// 读取多值查询参数，忽略空值
+func getQueryParams(c *gin.Context, name string) []string {
+	result := []string{}
+
+	for _, val := range c.Request.URL.Query()[name] {
+		if val = strings.TrimSpace(val); val != "" {
+			result = append(result, val)
+		}
+	}
+
+	return result
+}
+
+// 读取多值表单参数，包含查询参数及请求体中的表单，忽略空值
+func getFormValues(c *gin.Context, name string) []string {
+	result := []string{}
+
+	if c.Request.Form == nil {
+		c.Request.ParseMultipartForm(32 << 20) //nolint
+	}
+
+	for _, val := range c.Request.Form[name] {
+		if val = strings.TrimSpace(val); val != "" {
+			result = append(result, val)
+		}
+	}
+
+	return result
+}
+
 // 从 Form 中解析 Int 值
 func parseIntFormValue(c *gin.Context, name string, defValue int) (int, error) {
 	val := c.Request.FormValue(name)
@@ -125,6 +170,266 @@ func parseIntFormValue(c *gin.Context, name string, defValue int) (int, error) {
 	return num, nil
 }
 
+// 从 Form 中解析 Bool 值
+func parseBoolFormValue(c *gin.Context, name string, defValue bool) (bool, error) {
+	val := c.Request.FormValue(name)
+
+	if val == "" {
+		return defValue, nil
+	}
+
+	result, err := strconv.ParseBool(val)
+	if err != nil {
+		return defValue, fmt.Errorf("%s must be a boolean", name)
+	}
+
+	return result, nil
+}
+
+// 从查询参数中解析 Bool 值
+func parseBoolQueryParam(c *gin.Context, name string, defValue bool) (bool, error) {
+	val := getQueryParam(c, name)
+
+	if val == "" {
+		return defValue, nil
+	}
+
+	result, err := strconv.ParseBool(val)
+	if err != nil {
+		return defValue, fmt.Errorf("%s must be a boolean", name)
+	}
+
+	return result, nil
+}
+
+// 从查询参数中解析单个字符，支持 tab 别名
+func parseCharQueryParam(c *gin.Context, name string) (rune, error) {
+	val := getQueryParam(c, name)
+
+	switch val {
+	case "":
+		return 0, nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+
+	chars := []rune(val)
+	if len(chars) != 1 {
+		return 0, fmt.Errorf("%s must be a single character", name)
+	}
+
+	return chars[0], nil
+}
+
+// 解析导入参数，参数仅从 URL 中读取，以免提前读取上传的文件
+func parseImportOptions(c *gin.Context, table string, filename string) (*client.Import, error) {
+	var err error
+
+	imp := &client.Import{
+		Table:  table,
+		Format: getQueryParam(c, "format"),
+		Null:   getQueryParam(c, "null"),
+	}
+
+	if imp.Format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".ndjson", ".jsonl":
+			imp.Format = client.ImportFormatNDJSON
+		default:
+			imp.Format = client.ImportFormatCSV
+		}
+	}
+
+	if imp.Delimiter, err = parseCharQueryParam(c, "delimiter"); err != nil {
+		return nil, err
+	}
+	if imp.Quote, err = parseCharQueryParam(c, "quote"); err != nil {
+		return nil, err
+	}
+	if imp.Header, err = parseBoolQueryParam(c, "header", true); err != nil {
+		return nil, err
+	}
+	if imp.DryRun, err = parseBoolQueryParam(c, "dry_run", false); err != nil {
+		return nil, err
+	}
+
+	if val := getQueryParam(c, "dry_run_rows"); val != "" {
+		if imp.DryRunRows, err = strconv.Atoi(val); err != nil || imp.DryRunRows < 1 {
+			return nil, fmt.Errorf("dry_run_rows must be greater than 0")
+		}
+	}
+
+	// Empty entries skip the corresponding input fields, ie "id,,name"
+	if val := getQueryParam(c, "columns"); val != "" {
+		for _, name := range strings.Split(val, ",") {
+			imp.Columns = append(imp.Columns, strings.TrimSpace(name))
+		}
+	}
+
+	// Mapping is a list of "field:column" pairs
+	if val := getQueryParam(c, "mapping"); val != "" {
+		imp.Mapping = map[string]string{}
+		for _, pair := range strings.Split(val, ",") {
+			chunks := strings.SplitN(pair, ":", 2)
+			if len(chunks) != 2 || strings.TrimSpace(chunks[0]) == "" || strings.TrimSpace(chunks[1]) == "" {
+				return nil, fmt.Errorf("invalid mapping: %q", pair)
+			}
+			imp.Mapping[strings.TrimSpace(chunks[0])] = strings.TrimSpace(chunks[1])
+		}
+	}
+
+	return imp, nil
+}
+
+// 解析结果导出参数，CSV 格式支持 delimiter / quote_all / header / null / bom / time_format / timezone，
+// TSV 格式固定使用 tab 分隔且不加引号，不支持 delimiter / quote_all，其他格式忽略这些参数
+func parseExportOptions(c *gin.Context, format string) (client.ExportOptions, error) {
+	var err error
+
+	opts := client.ExportOptions{
+		Table: getQueryParam(c, "table"),
+	}
+	if format != "csv" && format != "tsv" {
+		return opts, nil
+	}
+	if format == "tsv" {
+		for _, name := range []string{"delimiter", "quote_all"} {
+			if getQueryParam(c, name) != "" {
+				return opts, fmt.Errorf("%s is not supported for tsv format", name)
+			}
+		}
+	}
+
+	opts.CSV = client.CSVOptions{
+		Null:       getQueryParam(c, "null"),
+		TimeFormat: getQueryParam(c, "time_format"),
+		Timezone:   getQueryParam(c, "timezone"),
+	}
+
+	if opts.CSV.Delimiter, err = parseCharQueryParam(c, "delimiter"); err != nil {
+		return opts, err
+	}
+	if opts.CSV.QuoteAll, err = parseBoolQueryParam(c, "quote_all", false); err != nil {
+		return opts, err
+	}
+	if opts.CSV.BOM, err = parseBoolQueryParam(c, "bom", false); err != nil {
+		return opts, err
+	}
+
+	header, err := parseBoolQueryParam(c, "header", true)
+	if err != nil {
+		return opts, err
+	}
+	opts.CSV.SkipHeader = !header
+
+	return opts, opts.CSV.Validate()
+}
+
+// 解析 pg_dump 导出参数，支持查询参数及表单，table / exclude_table / schema / exclude_schema 支持多个值
+func parseDumpOptions(c *gin.Context) (*client.Dump, error) {
+	dump := &client.Dump{
+		Format:         strings.TrimSpace(c.Request.FormValue("format")),
+		Tables:         getFormValues(c, "table"),
+		ExcludeTables:  getFormValues(c, "exclude_table"),
+		Schemas:        getFormValues(c, "schema"),
+		ExcludeSchemas: getFormValues(c, "exclude_schema"),
+	}
+
+	flags := []struct {
+		name string
+		dst  *bool
+	}{
+		{"schema_only", &dump.SchemaOnly},
+		{"data_only", &dump.DataOnly},
+		{"inserts", &dump.Inserts},
+		{"column_inserts", &dump.ColumnInserts},
+		{"no_privileges", &dump.NoPrivileges},
+	}
+
+	for _, flag := range flags {
+		val, err := parseBoolFormValue(c, flag.name, false)
+		if err != nil {
+			return nil, err
+		}
+		*flag.dst = val
+	}
+
+	if err := dump.ValidateOptions(); err != nil {
+		return nil, err
+	}
+
+	return dump, nil
+}
+
+// 读取上传的文件，支持 multipart 中的 file 字段，或者直接使用请求体
+func getUploadedFile(c *gin.Context) (io.ReadCloser, string, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		if c.Request.Body == nil || c.Request.ContentLength == 0 {
+			return nil, "", errFileRequired
+		}
+		return c.Request.Body, "", nil
+	}
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, "", errFileRequired
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if part.FormName() == "file" {
+			return part, part.FileName(), nil
+		}
+		part.Close()
+	}
+}
+
+// 将上传的文件保存到临时文件，供后台任务读取
+func spoolUpload(input io.Reader) (*os.File, error) {
+	file, err := os.CreateTemp("", "pgweb-import-*")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.Copy(file, input); err != nil {
+		removeSpooledUpload(file)
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		removeSpooledUpload(file)
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// 关闭并删除临时文件
+func removeSpooledUpload(file *os.File) {
+	file.Close()
+	os.Remove(file.Name())
+}
+
+// flushWriter flushes every write to stream the output to the client
+type flushWriter struct {
+	w gin.ResponseWriter
+}
+
+func newFlushWriter(w gin.ResponseWriter) *flushWriter {
+	return &flushWriter{w: w}
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.w.Flush()
+	return n, err
+}
+
 // 解析Ssh Info
 func parseSshInfo(c *gin.Context) *shared.SSHInfo {
 	info := shared.SSHInfo{
@@ -169,6 +474,63 @@ func serveResult(c *gin.Context, result interface{}, err interface{}) {
 	successResponse(c, result)
 }
 
+// Send a query result exported with the registered exporter for the format.
+// Result is sent as an attachment when filename is set.
+// 使用指定格式导出查询结果
+func serveExport(c *gin.Context, res *client.Result, format string, filename string) {
+	exporter, err := client.GetExporter(format)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	opts, err := parseExportOptions(c, format)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	// Export into a buffer first so errors could be still reported to client
+	buf := bytes.NewBuffer(nil)
+	if err := exporter.Export(buf, res, opts); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	if filename != "" {
+		if filepath.Ext(filename) == "" {
+			filename = filename + "." + exporter.Extension()
+		}
+		c.Writer.Header().Set("Content-disposition", "attachment;filename="+filename)
+	}
+
+	c.Data(http.StatusOK, exporter.ContentType(), buf.Bytes())
+}
+
+// Send a query result to client, exported when the format parameter is set.
+// Result is saved as an attachment named after the given name when export parameter is set.
+// 发送可导出的查询结果
+func serveExportableResult(c *gin.Context, res *client.Result, name string) {
+	format := getQueryParam(c, "format")
+	export := getQueryParam(c, "export") == "true"
+
+	filename := ""
+	if export {
+		filename = fmt.Sprintf("pgweb-%s-%s", name, time.Now().Format(time.DateOnly))
+	}
+
+	// JSON keeps the result encoding (columns / rows / stats) when exported
+	if format == "" || format == "json" {
+		if filename != "" {
+			c.Writer.Header().Set("Content-disposition", "attachment;filename="+filename+".json")
+		}
+		successResponse(c, res)
+		return
+	}
+
+	serveExport(c, res, format, filename)
+}
+
 // Send successful response back to client
 // 成功响应
 func successResponse(c *gin.Context, data interface{}) {
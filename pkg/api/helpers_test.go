@@ -2,13 +2,18 @@ package api
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/sosedoff/pgweb/pkg/client"
 )
 
 func Test_desanitize64(t *testing.T) {
@@ -86,3 +91,163 @@ func Test_serveResult(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 	assert.Equal(t, `null`, w.Body.String())
 }
+
+func Test_serveExportableResult(t *testing.T) {
+	res := &client.Result{Columns: []string{"id"}, Rows: []client.Row{{1}}}
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/?"+query, nil)
+		serveExportableResult(c, res, "dbstats-booktown")
+		return w
+	}
+
+	w := serve("")
+	assert.Equal(t, `{"columns":["id"],"rows":[[1]]}`, w.Body.String())
+
+	// Exported JSON keeps the result encoding
+	w = serve("format=json&export=true")
+	assert.Equal(t, `{"columns":["id"],"rows":[[1]]}`, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-disposition"), "attachment;filename=pgweb-dbstats-booktown-")
+	assert.True(t, strings.HasSuffix(w.Header().Get("Content-disposition"), ".json"))
+
+	w = serve("format=csv")
+	assert.Equal(t, "id\n1\n", w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-disposition"))
+}
+
+func Test_parseImportOptions(t *testing.T) {
+	parse := func(query string, filename string) (*client.Import, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("POST", "/?"+query, nil)
+		return parseImportOptions(c, "public.books", filename)
+	}
+
+	imp, err := parse("", "data.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "public.books", imp.Table)
+	assert.Equal(t, client.ImportFormatCSV, imp.Format)
+	assert.True(t, imp.Header)
+	assert.False(t, imp.DryRun)
+
+	imp, err = parse("", "data.jsonl")
+	assert.NoError(t, err)
+	assert.Equal(t, client.ImportFormatNDJSON, imp.Format)
+
+	imp, err = parse("delimiter=tab&quote='&header=false&null=NULL&dry_run=1&dry_run_rows=5&columns=id,,title", "")
+	assert.NoError(t, err)
+	assert.Equal(t, '\t', imp.Delimiter)
+	assert.Equal(t, '\'', imp.Quote)
+	assert.False(t, imp.Header)
+	assert.Equal(t, "NULL", imp.Null)
+	assert.True(t, imp.DryRun)
+	assert.Equal(t, 5, imp.DryRunRows)
+	assert.Equal(t, []string{"id", "", "title"}, imp.Columns)
+
+	imp, err = parse("mapping=Name:title,+ID+:id", "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Name": "title", "ID": "id"}, imp.Mapping)
+
+	_, err = parse("mapping=Name", "")
+	assert.EqualError(t, err, `invalid mapping: "Name"`)
+
+	_, err = parse("delimiter=ab", "")
+	assert.EqualError(t, err, "delimiter must be a single character")
+
+	_, err = parse("header=maybe", "")
+	assert.EqualError(t, err, "header must be a boolean")
+
+	_, err = parse("dry_run_rows=0", "")
+	assert.EqualError(t, err, "dry_run_rows must be greater than 0")
+}
+
+func Test_parseExportOptions(t *testing.T) {
+	parseFormat := func(format string, query string) (client.ExportOptions, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("GET", "/?"+query, nil)
+		return parseExportOptions(c, format)
+	}
+	parse := func(query string) (client.ExportOptions, error) {
+		return parseFormat("csv", query)
+	}
+
+	opts, err := parse("")
+	assert.NoError(t, err)
+	assert.Equal(t, ',', opts.CSV.Delimiter)
+	assert.False(t, opts.CSV.SkipHeader)
+	assert.False(t, opts.CSV.QuoteAll)
+	assert.Equal(t, "", opts.CSV.Null)
+
+	opts, err = parse("table=public.books&delimiter=tab&quote_all=true&header=false&null=NULL&bom=1&time_format=date&timezone=UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, "public.books", opts.Table)
+	assert.Equal(t, '\t', opts.CSV.Delimiter)
+	assert.True(t, opts.CSV.QuoteAll)
+	assert.True(t, opts.CSV.SkipHeader)
+	assert.True(t, opts.CSV.BOM)
+	assert.Equal(t, "NULL", opts.CSV.Null)
+	assert.Equal(t, "2006-01-02", opts.CSV.TimeFormat)
+	assert.Equal(t, "UTC", opts.CSV.Timezone)
+
+	_, err = parse("quote_all=maybe")
+	assert.EqualError(t, err, "quote_all must be a boolean")
+
+	_, err = parse("timezone=Nowhere")
+	assert.EqualError(t, err, "invalid timezone: Nowhere")
+
+	_, err = parseFormat("tsv", "delimiter=|")
+	assert.EqualError(t, err, "delimiter is not supported for tsv format")
+
+	_, err = parseFormat("tsv", "quote_all=true")
+	assert.EqualError(t, err, "quote_all is not supported for tsv format")
+
+	opts, err = parseFormat("tsv", "header=false&null=\\N&bom=true&timezone=UTC")
+	assert.NoError(t, err)
+	assert.True(t, opts.CSV.SkipHeader)
+	assert.True(t, opts.CSV.BOM)
+	assert.Equal(t, `\N`, opts.CSV.Null)
+
+	// CSV options are not validated for other formats
+	for _, format := range []string{"json", "xlsx"} {
+		opts, err = parseFormat(format, "table=public.books&delimiter=ab&bom=maybe&timezone=Nowhere")
+		assert.NoError(t, err)
+		assert.Equal(t, "public.books", opts.Table)
+	}
+}
+
+func Test_parseDumpOptions(t *testing.T) {
+	parse := func(query string, form string) (*client.Dump, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("POST", "/?"+query, strings.NewReader(form))
+		c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return parseDumpOptions(c)
+	}
+
+	dump, err := parse("table=public.books&table=public.authors&schema_only=true", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"public.books", "public.authors"}, dump.Tables)
+	assert.True(t, dump.SchemaOnly)
+
+	dump, err = parse("", "table=public.books&exclude_schema=audit&inserts=1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"public.books"}, dump.Tables)
+	assert.Equal(t, []string{"audit"}, dump.ExcludeSchemas)
+	assert.True(t, dump.Inserts)
+
+	_, err = parse("", "data_only=maybe")
+	assert.EqualError(t, err, "data_only must be a boolean")
+}
+
+func Test_spoolUpload(t *testing.T) {
+	file, err := spoolUpload(strings.NewReader("id,title\n1,Dune\n"))
+	assert.NoError(t, err)
+
+	data, err := io.ReadAll(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,title\n1,Dune\n", string(data))
+
+	removeSpooledUpload(file)
+	_, err = os.Stat(file.Name())
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,376 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tuvistavie/securerandom"
+)
+
+// 任务状态
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+
+	// 查询任务类型
+	JobKindQuery = "query"
+
+	// 表数据导入任务类型
+	JobKindImport = "import"
+
+	// 已结束任务的默认保留时间
+	defaultJobRetention = time.Hour
+)
+
+// 任务执行函数，通过 progress 上报任务进度
+type JobFunc func(ctx context.Context, progress func(value interface{})) (interface{}, error)
+
+// JobOutput is implemented by large job results, the job status only contains
+// the summary while the full output is retrieved with Output. Size is counted
+// towards the memory limit of retained outputs.
+// 任务结果较大时实现该接口，任务状态中只返回摘要
+type JobOutput interface {
+	Summary() interface{}
+	Size() int64
+}
+
+// 后台任务
+type Job struct {
+	ID         string      `json:"id"`
+	Kind       string      `json:"kind"`               // 任务类型
+	Status     string      `json:"status"`             // 任务状态
+	Progress   interface{} `json:"progress,omitempty"` // 任务进度
+	Result     interface{} `json:"result,omitempty"`   // 任务结果
+	Error      string      `json:"error,omitempty"`    // 错误信息
+	CreatedAt  time.Time   `json:"created_at"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty"` // 任务及结果的过期时间
+
+	session    string             // 所属会话
+	cancel     context.CancelFunc // 取消任务
+	output     JobOutput          // 完整的任务结果
+	outputSize int64              // 完整的任务结果大小
+}
+
+// Finished returns true if the job is no longer running
+func (job Job) Finished() bool {
+	return job.Status == JobCompleted || job.Status == JobFailed || job.Status == JobCancelled
+}
+
+// 任务管理器，基于 sync.Mutex + map 实现
+type JobManager struct {
+	logger    *logrus.Logger  // 日志
+	jobs      map[string]*Job // 所有任务
+	mu        sync.Mutex      // 锁
+	retention time.Duration   // 已结束任务的保留时间
+	limits    map[string]int  // 每个会话可同时运行的任务数，按任务类型限制
+
+	outputSize    int64 // 保留的任务结果总大小
+	maxOutputSize int64 // 保留的任务结果总大小上限，0 表示不限制
+}
+
+func NewJobManager(logger *logrus.Logger) *JobManager {
+	return &JobManager{
+		logger:    logger,
+		jobs:      map[string]*Job{},
+		mu:        sync.Mutex{},
+		retention: defaultJobRetention,
+		limits:    map[string]int{},
+	}
+}
+
+func (m *JobManager) SetRetention(retention time.Duration) {
+	m.retention = retention
+}
+
+// 设置每个会话可同时运行的指定类型任务数，0 表示不限制
+func (m *JobManager) SetLimit(kind string, limit int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limits[kind] = limit
+}
+
+// 设置保留的任务结果总大小上限，超出上限的任务结果被丢弃，任务失败
+func (m *JobManager) SetMaxOutputSize(size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.maxOutputSize = size
+}
+
+// 启动后台任务，返回任务快照
+func (m *JobManager) Start(kind string, session string, fn JobFunc) (Job, error) {
+	id, err := securerandom.Uuid()
+	if err != nil {
+		return Job{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	job := &Job{
+		ID:        id,
+		Kind:      kind,
+		Status:    JobQueued,
+		CreatedAt: time.Now().UTC(),
+		session:   session,
+		cancel:    cancel,
+	}
+
+	m.mu.Lock()
+	if limit := m.limits[kind]; limit > 0 && m.running(kind, session) >= limit {
+		m.mu.Unlock()
+		cancel()
+		return Job{}, fmt.Errorf("%w: %d %s jobs are already running", errJobLimitReached, limit, kind)
+	}
+	m.jobs[id] = job
+	snapshot := *job
+	m.mu.Unlock()
+
+	go m.run(ctx, job, fn)
+
+	return snapshot, nil
+}
+
+func (m *JobManager) run(ctx context.Context, job *Job, fn JobFunc) {
+	defer job.cancel()
+
+	m.update(func() {
+		now := time.Now().UTC()
+		job.Status = JobRunning
+		job.StartedAt = &now
+	})
+
+	result, err := m.execute(ctx, job, fn)
+	status := ""
+
+	output, hasOutput := result.(JobOutput)
+	var outputSize int64
+	if hasOutput && err == nil {
+		outputSize = output.Size()
+	}
+
+	m.update(func() {
+		now := time.Now().UTC()
+		expires := now.Add(m.retention)
+		job.FinishedAt = &now
+		job.ExpiresAt = &expires
+		job.Result = result
+
+		if hasOutput && err == nil {
+			if m.maxOutputSize > 0 && m.outputSize+outputSize > m.maxOutputSize {
+				job.Result = nil
+				err = fmt.Errorf("%w: %d bytes of results are retained", errJobMemoryLimit, m.outputSize)
+			} else {
+				job.Result = output.Summary()
+				job.output = output
+				job.outputSize = outputSize
+				m.outputSize += outputSize
+			}
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			job.Status = JobCancelled
+			job.Error = "job was cancelled"
+		case err != nil:
+			job.Status = JobFailed
+			job.Error = err.Error()
+		default:
+			job.Status = JobCompleted
+		}
+		status = job.Status
+	})
+
+	if m.logger != nil {
+		m.logger.WithFields(logrus.Fields{"id": job.ID, "kind": job.Kind, "status": status}).Debug("job finished")
+	}
+}
+
+// 执行任务函数，防止任务 panic 导致进程退出
+func (m *JobManager) execute(ctx context.Context, job *Job, fn JobFunc) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return fn(ctx, func(value interface{}) {
+		m.update(func() {
+			job.Progress = value
+		})
+	})
+}
+
+func (m *JobManager) update(fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fn()
+}
+
+// 获取指定会话的任务快照
+func (m *JobManager) Get(id string, session string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok || job.session != session {
+		return Job{}, false
+	}
+
+	return *job, true
+}
+
+// 获取指定会话的已完成任务及其完整结果
+func (m *JobManager) Output(id string, session string) (Job, JobOutput, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok || job.session != session {
+		return Job{}, nil, false
+	}
+
+	return *job, job.output, true
+}
+
+// 获取指定会话的所有任务快照
+func (m *JobManager) List(session string) []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := []Job{}
+	for _, job := range m.jobs {
+		if job.session == session {
+			jobs = append(jobs, *job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return jobs
+}
+
+// 取消指定会话的任务
+func (m *JobManager) Cancel(id string, session string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok || job.session != session {
+		return errJobNotFound
+	}
+	if job.Finished() {
+		return errors.New("job is already finished")
+	}
+
+	job.cancel()
+	return nil
+}
+
+// 取消并移除指定会话的所有任务，在会话关闭时调用
+func (m *JobManager) RemoveSession(session string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for id, job := range m.jobs {
+		if job.session != session {
+			continue
+		}
+		if !job.Finished() {
+			job.cancel()
+		}
+		m.remove(id)
+		removed++
+	}
+
+	return removed
+}
+
+// 移除任务并释放任务结果占用的空间，调用方需持有锁
+func (m *JobManager) remove(id string) {
+	if job, ok := m.jobs[id]; ok {
+		m.outputSize -= job.outputSize
+		delete(m.jobs, id)
+	}
+}
+
+// 保留的任务结果总大小
+func (m *JobManager) OutputSize() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.outputSize
+}
+
+// 指定会话是否有未结束的任务，运行任务的会话不视为空闲
+func (m *JobManager) HasRunning(session string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range m.jobs {
+		if job.session == session && !job.Finished() {
+			return true
+		}
+	}
+	return false
+}
+
+// 指定会话中未结束的指定类型任务数，调用方需持有锁
+func (m *JobManager) running(kind string, session string) int {
+	count := 0
+	for _, job := range m.jobs {
+		if job.session == session && job.Kind == kind && !job.Finished() {
+			count++
+		}
+	}
+	return count
+}
+
+// 任务总数
+func (m *JobManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.jobs)
+}
+
+// 清理超过保留时间的已结束任务
+func (m *JobManager) Cleanup() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	now := time.Now()
+
+	for id, job := range m.jobs {
+		if job.Finished() && job.FinishedAt != nil && now.Sub(*job.FinishedAt) > m.retention {
+			m.remove(id)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// 每分钟执行一次清理
+func (m *JobManager) RunPeriodicCleanup() {
+	for range time.Tick(time.Minute) {
+		if removed := m.Cleanup(); removed > 0 && m.logger != nil {
+			m.logger.Debug("removed finished jobs:", removed)
+		}
+	}
+}
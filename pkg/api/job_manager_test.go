@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sosedoff/pgweb/pkg/client"
+)
+
+func waitForJob(t *testing.T, manager *JobManager, id string, session string) Job {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		job, ok := manager.Get(id, session)
+		require.True(t, ok)
+		if job.Finished() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("job did not finish in time")
+	return Job{}
+}
+
+func TestJobManager(t *testing.T) {
+	t.Run("complete job", func(t *testing.T) {
+		manager := NewJobManager(logrus.New())
+
+		job, err := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			progress(50)
+			return "done", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, JobQueued, job.Status)
+		assert.Equal(t, "test", job.Kind)
+
+		job = waitForJob(t, manager, job.ID, "foo")
+		assert.Equal(t, JobCompleted, job.Status)
+		assert.Equal(t, "done", job.Result)
+		assert.Equal(t, 50, job.Progress)
+		assert.NotNil(t, job.StartedAt)
+		assert.NotNil(t, job.FinishedAt)
+		assert.Empty(t, job.Error)
+	})
+
+	t.Run("failed job", func(t *testing.T) {
+		manager := NewJobManager(nil)
+
+		job, err := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return nil, errors.New("boom")
+		})
+		assert.NoError(t, err)
+
+		job = waitForJob(t, manager, job.ID, "foo")
+		assert.Equal(t, JobFailed, job.Status)
+		assert.Equal(t, "boom", job.Error)
+	})
+
+	t.Run("panicking job", func(t *testing.T) {
+		manager := NewJobManager(nil)
+
+		job, _ := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			panic("oops")
+		})
+
+		job = waitForJob(t, manager, job.ID, "foo")
+		assert.Equal(t, JobFailed, job.Status)
+		assert.Equal(t, "job panicked: oops", job.Error)
+	})
+
+	t.Run("cancel job", func(t *testing.T) {
+		manager := NewJobManager(nil)
+
+		job, _ := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		assert.Equal(t, errJobNotFound, manager.Cancel(job.ID, "bar"))
+		assert.NoError(t, manager.Cancel(job.ID, "foo"))
+
+		job = waitForJob(t, manager, job.ID, "foo")
+		assert.Equal(t, JobCancelled, job.Status)
+		assert.EqualError(t, manager.Cancel(job.ID, "foo"), "job is already finished")
+	})
+
+	t.Run("session isolation", func(t *testing.T) {
+		manager := NewJobManager(nil)
+
+		job, _ := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return nil, nil
+		})
+
+		_, ok := manager.Get(job.ID, "bar")
+		assert.False(t, ok)
+		assert.Len(t, manager.List("foo"), 1)
+		assert.Len(t, manager.List("bar"), 0)
+	})
+
+	t.Run("clean up finished jobs", func(t *testing.T) {
+		manager := NewJobManager(nil)
+
+		job, _ := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return nil, nil
+		})
+		waitForJob(t, manager, job.ID, "foo")
+
+		assert.Equal(t, 0, manager.Cleanup())
+		assert.Equal(t, 1, manager.Len())
+
+		manager.SetRetention(0)
+		assert.Equal(t, 1, manager.Cleanup())
+		assert.Equal(t, 0, manager.Len())
+	})
+
+	t.Run("session job limit", func(t *testing.T) {
+		manager := NewJobManager(nil)
+		manager.SetLimit("test", 1)
+
+		release := make(chan struct{})
+		job, err := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			<-release
+			return nil, nil
+		})
+		assert.NoError(t, err)
+
+		_, err = manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return nil, nil
+		})
+		assert.ErrorIs(t, err, errJobLimitReached)
+		assert.EqualError(t, err, "Job limit reached: 1 test jobs are already running")
+
+		// Other sessions and job kinds are not limited
+		_, err = manager.Start("test", "bar", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return nil, nil
+		})
+		assert.NoError(t, err)
+		_, err = manager.Start("other", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return nil, nil
+		})
+		assert.NoError(t, err)
+
+		close(release)
+		waitForJob(t, manager, job.ID, "foo")
+
+		_, err = manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return nil, nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("job output", func(t *testing.T) {
+		manager := NewJobManager(nil)
+
+		result := &client.Result{Columns: []string{"id"}, Rows: []client.Row{{1}, {2}}}
+		job, _ := manager.Start(JobKindQuery, "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return queryJobOutput{result: result}, nil
+		})
+
+		job = waitForJob(t, manager, job.ID, "foo")
+		assert.Equal(t, 2, job.Result.(map[string]interface{})["rows_count"])
+		assert.NotNil(t, job.ExpiresAt)
+		assert.Equal(t, job.FinishedAt.Add(defaultJobRetention), *job.ExpiresAt)
+
+		_, output, ok := manager.Output(job.ID, "foo")
+		assert.True(t, ok)
+		assert.Equal(t, result, output.(queryJobOutput).result)
+
+		_, _, ok = manager.Output(job.ID, "bar")
+		assert.False(t, ok)
+	})
+
+	t.Run("output memory limit", func(t *testing.T) {
+		manager := NewJobManager(nil)
+
+		result := &client.Result{Columns: []string{"id"}, Rows: []client.Row{{"a"}, {"b"}}}
+		size := result.Size()
+		manager.SetMaxOutputSize(size + 1)
+
+		start := func(session string) Job {
+			job, err := manager.Start(JobKindQuery, session, func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+				return queryJobOutput{result: result}, nil
+			})
+			require.NoError(t, err)
+			return waitForJob(t, manager, job.ID, session)
+		}
+
+		first := start("foo")
+		assert.Equal(t, JobCompleted, first.Status)
+		assert.Equal(t, size, manager.OutputSize())
+
+		second := start("bar")
+		assert.Equal(t, JobFailed, second.Status)
+		assert.Contains(t, second.Error, errJobMemoryLimit.Error())
+		assert.Nil(t, second.Result)
+
+		_, output, _ := manager.Output(second.ID, "bar")
+		assert.Nil(t, output)
+
+		assert.Equal(t, 1, manager.RemoveSession("foo"))
+		assert.Equal(t, int64(0), manager.OutputSize())
+
+		assert.Equal(t, JobCompleted, start("bar").Status)
+	})
+
+	t.Run("remove session jobs", func(t *testing.T) {
+		manager := NewJobManager(nil)
+
+		cancelled := make(chan struct{})
+		running, _ := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		})
+		finished, _ := manager.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			return nil, nil
+		})
+		waitForJob(t, manager, finished.ID, "foo")
+		manager.Start("test", "bar", func(ctx context.Context, progress func(interface{})) (interface{}, error) { //nolint
+			return nil, nil
+		})
+
+		assert.Equal(t, 2, manager.RemoveSession("foo"))
+		<-cancelled
+
+		_, ok := manager.Get(running.ID, "foo")
+		assert.False(t, ok)
+		assert.Len(t, manager.List("foo"), 0)
+		assert.Len(t, manager.List("bar"), 1)
+	})
+}
@@ -52,8 +52,32 @@ func SetupRoutes(router *gin.Engine) {
 	api.GET("/connection", GetConnectionInfo)
 	// /api/server_settings => 获取服务设置
 	api.GET("/server_settings", GetServerSettings)
+	// /api/settings => 按分类获取服务器设置及其来源
+	api.GET("/settings", GetSettings)
+	// /api/settings/compare => 比较两个会话或书签连接的服务器设置
+	api.GET("/settings/compare", CompareSettings)
+	// /api/settings/reload => 重新加载服务器配置
+	api.POST("/settings/reload", ReloadSettings)
+	// /api/settings/:name => 通过 ALTER SYSTEM 修改设置
+	api.POST("/settings/:name", SetSetting)
+	// /api/settings/:name/reset => 通过 ALTER SYSTEM RESET 重置设置
+	api.POST("/settings/:name/reset", ResetSetting)
 	// /api/activity => 获取当前活跃的查询
 	api.GET("/activity", GetActivity)
+	// /api/activity/locks => 获取锁等待的阻塞关系树
+	api.GET("/activity/locks", GetBlockingTree)
+	// /api/activity/:pid/cancel => 取消后端进程正在执行的查询
+	api.POST("/activity/:pid/cancel", CancelBackend)
+	// /api/activity/:pid/terminate => 终止后端进程
+	api.POST("/activity/:pid/terminate", TerminateBackend)
+	// /api/replication => 获取主备复制状态及 WAL 延迟
+	api.GET("/replication", GetReplication)
+	// /api/roles => 获取角色属性及成员关系
+	api.GET("/roles", GetRoles)
+	// /api/privileges => 获取表、schema 或函数上每个角色的有效权限
+	api.GET("/privileges", GetPrivileges)
+	// /api/default_privileges => 获取默认权限
+	api.GET("/default_privileges", GetDefaultPrivileges)
 	// /api/schemas => 获取 schema
 	api.GET("/schemas", GetSchemas)
 	// /api/objects => 获取对象
@@ -68,13 +92,75 @@ func SetupRoutes(router *gin.Engine) {
 	api.GET("/tables/:table/indexes", GetTableIndexes)
 	// /api/tables/:table/constraints => 获取表约束
 	api.GET("/tables/:table/constraints", GetTableConstraints)
+	// /api/tables/:table/import => 导入 CSV / NDJSON 数据到表中
+	api.POST("/tables/:table/import", ImportTable)
+	// /api/tables/:table/stats => 获取表中每列的 pg_stats 统计信息
+	api.GET("/tables/:table/stats", GetColumnStats)
+	// /api/tables/:table/profile => 获取缓存的表数据分析结果
+	api.GET("/tables/:table/profile", GetTableProfile)
+	// /api/tables/:table/profile => 后台任务分析表中每列的数据
+	api.POST("/tables/:table/profile", ProfileTable)
+	// /api/tables/:table/vacuum => 后台执行 VACUUM (ANALYZE)
+	api.POST("/tables/:table/vacuum", VacuumTable)
+	// /api/tables/:table/analyze => 后台执行 ANALYZE
+	api.POST("/tables/:table/analyze", AnalyzeTable)
+	// /api/tables/:table/reindex => 后台执行 REINDEX CONCURRENTLY
+	api.POST("/tables/:table/reindex", ReindexTable)
 	// /api/tables_stats => 获取表统计数据
 	api.GET("/tables_stats", GetTablesStats)
+	// /api/index_health => 获取索引健康报告
+	api.GET("/index_health", GetIndexHealth)
+	// /api/bloat => 获取表和索引的膨胀估算
+	api.GET("/bloat", GetBloat)
+	// /api/maintenance => 获取表的 vacuum / analyze 状态
+	api.GET("/maintenance", GetMaintenance)
+	// /api/jobs => 获取当前会话的后台任务
+	api.GET("/jobs", GetJobs)
+	// /api/jobs/:id => 获取后台任务状态
+	api.GET("/jobs/:id", GetJob)
+	// /api/jobs/:id/cancel => 取消后台任务
+	api.POST("/jobs/:id/cancel", CancelJob)
+	// /api/jobs/:id/result => 获取或下载查询任务的结果
+	api.GET("/jobs/:id/result", GetJobResult)
+	// /api/value_search => 后台任务在所有表的列中搜索指定的值
+	api.POST("/value_search", SearchValue)
+	// /api/notifications => 订阅 LISTEN/NOTIFY 通道并以 Server-Sent Events 推送通知
+	api.GET("/notifications", ListenNotifications)
+	// /api/snapshots => 获取存在表大小快照的书签
+	api.GET("/snapshots", GetSnapshotBookmarks)
+	// /api/snapshots/:bookmark/growth => 获取快照中增长最快的表
+	api.GET("/snapshots/:bookmark/growth", GetTablesGrowth)
+	// /api/snapshots/:bookmark/tables/:table => 获取表在快照中的大小变化
+	api.GET("/snapshots/:bookmark/tables/:table", GetTableSizeHistory)
+	// /api/stat_statements => 获取 pg_stat_statements 统计的 Top 语句
+	api.GET("/stat_statements", GetStatStatements)
+	// /api/stat_statements/reset => 重置 pg_stat_statements 统计数据
+	api.POST("/stat_statements/reset", ResetStatStatements)
 	// /api/functions/:id => 获取函数
 	api.GET("/functions/:id", GetFunction)
+	// /api/partitioned_tables/:id => 获取分区表的分区键及分区
+	api.GET("/partitioned_tables/:id", GetPartitionedTable)
+	// /api/foreign_tables/:id => 获取外部表
+	api.GET("/foreign_tables/:id", GetForeignTable)
+	// /api/triggers/:id => 获取触发器
+	api.GET("/triggers/:id", GetTrigger)
+	// /api/types/:id => 获取自定义类型
+	api.GET("/types/:id", GetType)
+	// /api/extensions/:id => 获取扩展
+	api.GET("/extensions/:id", GetExtension)
+	// /api/search => 按名称及注释搜索数据库对象
+	api.GET("/search", SearchObjects)
+	// /api/dependencies => 获取对象的依赖树
+	api.GET("/dependencies", GetDependencies)
+	// /api/event_triggers => 获取事件触发器列表
+	api.GET("/event_triggers", GetEventTriggers)
+	// /api/event_triggers/:id => 获取事件触发器
+	api.GET("/event_triggers/:id", GetEventTrigger)
 	// /api/query => 执行查询，GET / POST
 	api.GET("/query", RunQuery)
 	api.POST("/query", RunQuery)
+	// /api/query_jobs => 后台任务执行查询
+	api.POST("/query_jobs", StartQueryJob)
 	// /api/explain => 执行解释，GET / POST
 	api.GET("/explain", ExplainQuery)
 	api.POST("/explain", ExplainQuery)
@@ -87,6 +173,8 @@ func SetupRoutes(router *gin.Engine) {
 	api.GET("/bookmarks", GetBookmarks)
 	// /api/export => 导出
 	api.GET("/export", DataExport)
+	// /api/import => 导入 dump 文件
+	api.POST("/import", DataImport)
 	// /api/local_queries => 获取本地查询
 	api.GET("/local_queries", requireLocalQueries(), GetLocalQueries)
 	// /api/local_queries/:id => 获取本地查询，GET / POST
@@ -16,6 +16,7 @@ type SessionManager struct {
 	sessions    map[string]*client.Client // 不同会话的客户端
 	mu          sync.Mutex                // 锁
 	idleTimeout time.Duration             // 超时时间
+	jobs        *JobManager               // 会话关闭时取消的后台任务
 }
 
 func NewSessionManager(logger *logrus.Logger) *SessionManager {
@@ -30,6 +31,11 @@ func (m *SessionManager) SetIdleTimeout(timeout time.Duration) {
 	m.idleTimeout = timeout
 }
 
+// 设置任务管理器，会话移除时取消并移除其后台任务
+func (m *SessionManager) SetJobManager(jobs *JobManager) {
+	m.jobs = jobs
+}
+
 // 获取所有的会话 id
 func (m *SessionManager) IDs() []string {
 	m.mu.Lock()
@@ -82,6 +88,10 @@ func (m *SessionManager) Remove(id string) bool {
 	if ok {
 		conn.Close()
 		delete(m.sessions, id)
+
+		if m.jobs != nil {
+			m.jobs.RemoveSession(id)
+		}
 	}
 
 	metrics.SetSessionsCount(len(m.sessions))
@@ -130,7 +140,7 @@ func (m *SessionManager) RunPeriodicCleanup() {
 	}
 }
 
-// 返回超时会话的id列表
+// 返回超时会话的id列表，有未结束后台任务的会话不会超时
 func (m *SessionManager) staleSessions() []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -139,6 +149,9 @@ func (m *SessionManager) staleSessions() []string {
 	ids := []string{}
 
 	for id, conn := range m.sessions {
+		if m.jobs != nil && m.jobs.HasRunning(id) {
+			continue
+		}
 		if now.Sub(conn.LastQueryTime()) > m.idleTimeout {
 			ids = append(ids, id)
 		}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"sort"
 	"testing"
 	"time"
@@ -51,6 +52,22 @@ func TestSessionManager(t *testing.T) {
 		assert.Nil(t, manager.Get("foo"))
 	})
 
+	t.Run("remove session jobs", func(t *testing.T) {
+		jobs := NewJobManager(nil)
+		manager := NewSessionManager(nil)
+		manager.SetJobManager(jobs)
+
+		manager.Add("foo", &client.Client{})
+		jobs.Start("test", "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) { //nolint
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		assert.Equal(t, 1, jobs.Len())
+
+		assert.True(t, manager.Remove("foo"))
+		assert.Equal(t, 0, jobs.Len())
+	})
+
 	t.Run("return len", func(t *testing.T) {
 		manager := NewSessionManager(nil)
 		manager.sessions["foo"] = &client.Client{}
@@ -77,4 +94,29 @@ func TestSessionManager(t *testing.T) {
 		assert.Equal(t, 0, manager.Len())
 		assert.True(t, conn.IsClosed())
 	})
+
+	t.Run("keep sessions with running jobs", func(t *testing.T) {
+		jobs := NewJobManager(nil)
+		manager := NewSessionManager(logrus.New())
+		manager.SetJobManager(jobs)
+		manager.SetIdleTimeout(time.Minute)
+
+		manager.Add("foo", &client.Client{})
+		release := make(chan struct{})
+		job, _ := jobs.Start(JobKindQuery, "foo", func(ctx context.Context, progress func(interface{})) (interface{}, error) {
+			<-release
+			return nil, nil
+		})
+
+		assert.True(t, jobs.HasRunning("foo"))
+		assert.Equal(t, 0, manager.Cleanup())
+		assert.Equal(t, 1, manager.Len())
+
+		close(release)
+		waitForJob(t, jobs, job.ID, "foo")
+
+		assert.False(t, jobs.HasRunning("foo"))
+		assert.Equal(t, 1, manager.Cleanup())
+		assert.Equal(t, 0, manager.Len())
+	})
 }
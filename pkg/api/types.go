@@ -1,8 +1,41 @@
 package api
 
+import (
+	"github.com/sosedoff/pgweb/pkg/client"
+)
+
 type localQuery struct {
 	ID          string `json:"id"`
 	Title       string `json:"title,omitempty"`
 	Description string `json:"description,omitempty"`
 	Query       string `json:"query"`
 }
+
+// 查询任务进度
+type queryJobProgress struct {
+	Rows    int   `json:"rows"`       // 已接收的行数
+	Elapsed int64 `json:"elapsed_ms"` // 已执行时间
+}
+
+// 导入任务进度
+type importJobProgress struct {
+	Rows    int64 `json:"rows"`       // 已导入的行数
+	Elapsed int64 `json:"elapsed_ms"` // 已执行时间
+}
+
+// 查询任务结果，任务状态中只包含结果摘要，完整结果通过 /api/jobs/:id/result 获取
+type queryJobOutput struct {
+	result *client.Result
+}
+
+func (o queryJobOutput) Summary() interface{} {
+	return map[string]interface{}{
+		"columns":    o.result.Columns,
+		"rows_count": len(o.result.Rows),
+		"stats":      o.result.Stats,
+	}
+}
+
+func (o queryJobOutput) Size() int64 {
+	return o.result.Size()
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/sosedoff/pgweb/pkg/connection"
 	"github.com/sosedoff/pgweb/pkg/metrics"
 	"github.com/sosedoff/pgweb/pkg/queries"
+	"github.com/sosedoff/pgweb/pkg/snapshots"
 	"github.com/sosedoff/pgweb/pkg/util"
 )
 
@@ -176,6 +177,26 @@ func configureLocalQueryStore() {
 	api.QueryStore = queries.NewStore(options.QueriesDir)
 }
 
+func startSnapshotsCollector() {
+	ids := []string{}
+	for _, id := range strings.Split(options.SnapshotsBookmarks, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	api.Snapshots = snapshots.NewStore(options.SnapshotsDir)
+
+	collector := snapshots.NewCollector(
+		api.Snapshots,
+		bookmarks.NewManager(options.BookmarksDir),
+		ids,
+		time.Minute*time.Duration(options.SnapshotsInterval),
+		logger,
+	)
+	go collector.Run()
+}
+
 func configureLogger(opts command.Options) error {
 	// 设置日志级别，对于开启了 debug 开关，使用 debug 级别
 	if options.Debug {
@@ -334,9 +355,17 @@ func Run() {
 		util.StartProfiler()
 	}
 
+	// Start background jobs cleanup worker
+	api.Jobs = api.NewJobManager(logger)
+	api.Jobs.SetRetention(time.Minute * time.Duration(options.JobsRetention))
+	api.Jobs.SetLimit(api.JobKindQuery, options.QueryJobsLimit)
+	api.Jobs.SetMaxOutputSize(int64(options.QueryJobsMemory) * 1024 * 1024)
+	go api.Jobs.RunPeriodicCleanup()
+
 	// Start session cleanup worker
 	if options.Sessions {
 		api.DbSessions = api.NewSessionManager(logger)
+		api.DbSessions.SetJobManager(api.Jobs)
 
 		if !command.Opts.DisableConnectionIdleTimeout {
 			api.DbSessions.SetIdleTimeout(time.Minute * time.Duration(command.Opts.ConnectionIdleTimeout))
@@ -344,6 +373,11 @@ func Run() {
 		}
 	}
 
+	// Start table size snapshots collector
+	if options.Snapshots {
+		startSnapshotsCollector()
+	}
+
 	// Start a separate metrics http server. If metrics addr is not provided, we
 	// add the metrics endpoint in the existing application server (see api.go).
 	if options.MetricsEnabled && options.MetricsAddr != "" {
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/lib/pq"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	BackendActionCancel    = "cancel"
+	BackendActionTerminate = "terminate"
+
+	// SQLSTATE returned when the caller is not allowed to signal the backend
+	errCodeInsufficientPrivilege = "42501"
+)
+
+// BackendSignalResult contains the outcome of a backend cancel or terminate request
+type BackendSignalResult struct {
+	PID       int    `json:"pid"`
+	Action    string `json:"action"`
+	Delivered bool   `json:"delivered"`
+	Permitted bool   `json:"permitted"`
+	Message   string `json:"message,omitempty"`
+}
+
+// SignalBackend cancels the current query of the backend or terminates it.
+// Missing permissions are reported in the result instead of an error.
+func (client *Client) SignalBackend(pid int, action string) (*BackendSignalResult, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return nil, errors.New("backend signals are not supported on CockroachDB")
+	}
+	if pid <= 0 {
+		return nil, fmt.Errorf("invalid pid: %v", pid)
+	}
+
+	var query string
+	switch action {
+	case BackendActionCancel:
+		query = "SELECT pg_cancel_backend($1)"
+	case BackendActionTerminate:
+		query = "SELECT pg_terminate_backend($1)"
+	default:
+		return nil, fmt.Errorf("invalid backend action: %v", action)
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	result := &BackendSignalResult{
+		PID:       pid,
+		Action:    action,
+		Permitted: true,
+	}
+
+	err := client.db.QueryRowContext(ctx, query, pid).Scan(&result.Delivered)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == errCodeInsufficientPrivilege {
+			result.Permitted = false
+			result.Message = pqErr.Message
+			return result, nil
+		}
+		return nil, err
+	}
+
+	// Postgres only raises a warning when pid does not belong to a backend
+	if !result.Delivered {
+		result.Message = "backend process not found"
+	}
+
+	return result, nil
+}
+
+// BlockingProcess represents a backend that waits for a lock or holds a lock others wait for
+type BlockingProcess struct {
+	PID             int           `json:"pid" db:"pid"`
+	BlockedBy       pq.Int64Array `json:"blocked_by" db:"blocked_by"`
+	Username        string        `json:"username" db:"username"`
+	ApplicationName string        `json:"application_name" db:"application_name"`
+	State           string        `json:"state" db:"state"`
+	WaitEvent       string        `json:"wait_event" db:"wait_event"`
+	LockType        string        `json:"lock_type" db:"lock_type"`           // Type of the awaited lock
+	LockMode        string        `json:"lock_mode" db:"lock_mode"`           // Mode of the awaited lock
+	Relation        string        `json:"relation" db:"relation"`             // Relation of the awaited lock
+	HeldLocks       string        `json:"held_locks" db:"held_locks"`         // Granted relation locks
+	Query           string        `json:"query" db:"query"`                   // Current or last query text
+	QueryDuration   float64       `json:"query_duration" db:"query_duration"` // Seconds since the query start
+	StateDuration   *float64      `json:"state_duration" db:"state_duration"` // Seconds since the state change of a waiting process, an upper bound of the lock wait
+	WaitDuration    *float64      `json:"wait_duration" db:"wait_duration"`   // Seconds spent waiting for the lock, available since PostgreSQL 14
+}
+
+// BlockingNode is a process in the blocking tree along with processes it blocks
+type BlockingNode struct {
+	BlockingProcess
+	Blocked []*BlockingNode `json:"blocked"`
+}
+
+// BlockingTree returns the lock contention tree, root nodes are the processes
+// that block others while not being blocked themselves
+func (client *Client) BlockingTree() ([]*BlockingNode, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return nil, errors.New("blocking tree is not supported on CockroachDB")
+	}
+
+	major, minor := getMajorMinorVersion(client.serverVersion)
+	if major < 9 || (major == 9 && minor < 2) {
+		return nil, fmt.Errorf("blocking tree is not supported on PostgreSQL %v", client.serverVersion)
+	}
+
+	query := versionedStatement(statements.BlockingLocks, getMajorMinorVersionString(client.serverVersion))
+	// Start of the lock wait is tracked since 14, older versions only report the state duration
+	if major >= 14 {
+		query = statements.BlockingLocksWaitstart
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	procs := []BlockingProcess{}
+	if err := client.db.SelectContext(ctx, &procs, query); err != nil {
+		return nil, err
+	}
+
+	return buildBlockingTree(procs), nil
+}
+
+// buildBlockingTree nests processes under their blockers. A process blocked by
+// several others appears under each of them. Processes in a wait cycle have no
+// natural root, so the lowest pid of the cycle becomes one.
+func buildBlockingTree(procs []BlockingProcess) []*BlockingNode {
+	sort.Slice(procs, func(i, j int) bool {
+		return procs[i].PID < procs[j].PID
+	})
+
+	byPID := map[int]BlockingProcess{}
+	for _, proc := range procs {
+		byPID[proc.PID] = proc
+	}
+
+	children := map[int][]int{}
+	blocked := map[int]bool{}
+	edges := map[[2]int]bool{}
+	for _, proc := range procs {
+		for _, pid := range proc.BlockedBy {
+			blocker := int(pid)
+			if _, ok := byPID[blocker]; !ok || blocker == proc.PID {
+				continue
+			}
+
+			// pg_blocking_pids may list the same blocker more than once
+			edge := [2]int{blocker, proc.PID}
+			if edges[edge] {
+				continue
+			}
+			edges[edge] = true
+
+			children[blocker] = append(children[blocker], proc.PID)
+			blocked[proc.PID] = true
+		}
+	}
+
+	reached := map[int]bool{}
+	path := map[int]bool{}
+
+	var build func(pid int) *BlockingNode
+	build = func(pid int) *BlockingNode {
+		node := &BlockingNode{BlockingProcess: byPID[pid], Blocked: []*BlockingNode{}}
+		reached[pid] = true
+		path[pid] = true
+
+		for _, child := range children[pid] {
+			// Guard against wait cycles
+			if path[child] {
+				continue
+			}
+			node.Blocked = append(node.Blocked, build(child))
+		}
+
+		delete(path, pid)
+		return node
+	}
+
+	roots := []*BlockingNode{}
+	for _, proc := range procs {
+		if !blocked[proc.PID] {
+			roots = append(roots, build(proc.PID))
+		}
+	}
+	for _, proc := range procs {
+		if !reached[proc.PID] {
+			roots = append(roots, build(proc.PID))
+		}
+	}
+
+	return roots
+}
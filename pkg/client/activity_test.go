@@ -0,0 +1,67 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildBlockingTree(t *testing.T) {
+	pids := func(nodes []*BlockingNode) []int {
+		result := []int{}
+		for _, node := range nodes {
+			result = append(result, node.PID)
+		}
+		return result
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, []*BlockingNode{}, buildBlockingTree([]BlockingProcess{}))
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		tree := buildBlockingTree([]BlockingProcess{
+			{PID: 30, BlockedBy: []int64{20}},
+			{PID: 10},
+			{PID: 20, BlockedBy: []int64{10, 10}},
+			{PID: 40, BlockedBy: []int64{10}},
+		})
+
+		assert.Equal(t, []int{10}, pids(tree))
+		assert.Equal(t, []int{20, 40}, pids(tree[0].Blocked))
+		assert.Equal(t, []int{30}, pids(tree[0].Blocked[0].Blocked))
+		assert.Equal(t, []int{}, pids(tree[0].Blocked[1].Blocked))
+	})
+
+	t.Run("multiple blockers", func(t *testing.T) {
+		tree := buildBlockingTree([]BlockingProcess{
+			{PID: 1},
+			{PID: 2},
+			{PID: 3, BlockedBy: []int64{1, 2}},
+		})
+
+		assert.Equal(t, []int{1, 2}, pids(tree))
+		assert.Equal(t, []int{3}, pids(tree[0].Blocked))
+		assert.Equal(t, []int{3}, pids(tree[1].Blocked))
+	})
+
+	t.Run("unknown blocker", func(t *testing.T) {
+		tree := buildBlockingTree([]BlockingProcess{
+			{PID: 5, BlockedBy: []int64{99}},
+		})
+
+		assert.Equal(t, []int{5}, pids(tree))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		tree := buildBlockingTree([]BlockingProcess{
+			{PID: 7, BlockedBy: []int64{5}},
+			{PID: 5, BlockedBy: []int64{7}},
+			{PID: 8, BlockedBy: []int64{7}},
+		})
+
+		assert.Equal(t, []int{5}, pids(tree))
+		assert.Equal(t, []int{7}, pids(tree[0].Blocked))
+		assert.Equal(t, []int{8}, pids(tree[0].Blocked[0].Blocked))
+	})
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sosedoff/pgweb/pkg/command"
+)
+
+var (
+	// Detected versions of client binaries, keyed by path
+	binaryVersions = map[string]string{}
+	binaryMu       sync.Mutex
+)
+
+// clientBinary represents a PostgreSQL client binary such as pg_dump or pg_restore
+type clientBinary struct {
+	Path    string
+	Version string // Empty when the version could not be detected
+}
+
+// findClientBinary returns the path of the lowest version binary compatible with
+// the server. Candidates are taken from the configured binary paths and PATH.
+func findClientBinary(name string, serverVersion string) (string, error) {
+	binaries, err := findClientBinaries(name)
+	if err != nil {
+		return "", err
+	}
+
+	// Without the server version any binary works, prefer the most recent one
+	if serverVersion == "" {
+		return binaries[len(binaries)-1].Path, nil
+	}
+
+	for _, bin := range binaries {
+		if bin.Version == "" || checkVersionRequirement(bin.Version, serverVersion) {
+			return bin.Path, nil
+		}
+	}
+
+	latest := binaries[len(binaries)-1]
+	return "", fmt.Errorf("%s version %v not compatible with server version %v", name, latest.Version, serverVersion)
+}
+
+// findClientBinaries returns all usable binaries with the given name, ordered by version.
+// Binaries with unknown versions are placed last.
+func findClientBinaries(name string) ([]clientBinary, error) {
+	paths := binaryPaths(name, command.Opts.DumpBinPaths)
+	if path, err := exec.LookPath(name); err == nil {
+		paths = append(paths, path)
+	}
+
+	binaries := []clientBinary{}
+	seen := map[string]bool{}
+	var lastErr error
+
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		version, err := binaryVersion(path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		binaries = append(binaries, clientBinary{Path: path, Version: version})
+	}
+
+	if len(binaries) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("%s command not found", name)
+	}
+
+	sort.SliceStable(binaries, func(i, j int) bool {
+		a, b := binaries[i].Version, binaries[j].Version
+		if a == "" || b == "" {
+			return b == "" && a != ""
+		}
+		aMajor, aMinor := getMajorMinorVersion(a)
+		bMajor, bMinor := getMajorMinorVersion(b)
+		if aMajor != bMajor {
+			return aMajor < bMajor
+		}
+		return aMinor < bMinor
+	})
+
+	return binaries, nil
+}
+
+// binaryPaths expands the comma-separated list of directories or binaries.
+// Entries may contain glob patterns, ie /usr/lib/postgresql/*/bin
+func binaryPaths(name string, list string) []string {
+	paths := []string{}
+
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		matches, err := filepath.Glob(entry)
+		if err != nil {
+			continue
+		}
+
+		for _, match := range matches {
+			stat, err := os.Stat(match)
+			if err != nil {
+				continue
+			}
+
+			if stat.IsDir() {
+				candidate := filepath.Join(match, name)
+				if _, err := os.Stat(candidate); err == nil {
+					paths = append(paths, candidate)
+				}
+			} else if filepath.Base(match) == name {
+				paths = append(paths, match)
+			}
+		}
+	}
+
+	return paths
+}
+
+// binaryVersion runs the binary with --version flag and caches the detected version
+func binaryVersion(path string) (string, error) {
+	binaryMu.Lock()
+	defer binaryMu.Unlock()
+
+	if version, ok := binaryVersions[path]; ok {
+		return version, nil
+	}
+
+	out := bytes.NewBuffer(nil)
+
+	cmd := exec.Command(path, "--version")
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s command failed: %s", filepath.Base(path), out.Bytes())
+	}
+
+	_, version := detectDumpVersion(out.String())
+	binaryVersions[path] = version
+
+	return version, nil
+}
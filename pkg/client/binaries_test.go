@@ -0,0 +1,93 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sosedoff/pgweb/pkg/command"
+)
+
+// writeFakeBinary creates a shell script that prints the given version output
+func writeFakeBinary(t *testing.T, dir string, name string, output string) string {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	path := filepath.Join(dir, name)
+	script := fmt.Sprintf("#!/bin/sh\necho '%s'\n", output)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+
+	return path
+}
+
+func TestFindClientBinary(t *testing.T) {
+	if onWindows() {
+		t.Skip("shell scripts are not supported on Windows")
+	}
+
+	root := t.TempDir()
+	bin12 := writeFakeBinary(t, filepath.Join(root, "12", "bin"), "pg_dump", "pg_dump (PostgreSQL) 12.4")
+	bin15 := writeFakeBinary(t, filepath.Join(root, "15", "bin"), "pg_dump", "pg_dump (PostgreSQL) 15.1")
+	bin9 := writeFakeBinary(t, filepath.Join(root, "9.6", "bin"), "pg_dump", "pg_dump (PostgreSQL) 9.6.24")
+	custom := writeFakeBinary(t, filepath.Join(root, "custom"), "pg_dump", "pg_dump (PostgreSQL) 14.2")
+
+	t.Setenv("PATH", "")
+
+	defer func(val string) {
+		command.Opts.DumpBinPaths = val
+	}(command.Opts.DumpBinPaths)
+
+	command.Opts.DumpBinPaths = filepath.Join(root, "*", "bin") + ", " + custom
+
+	binaries, err := findClientBinaries("pg_dump")
+	require.NoError(t, err)
+	assert.Equal(t, []clientBinary{
+		{Path: bin9, Version: "9.6.24"},
+		{Path: bin12, Version: "12.4"},
+		{Path: custom, Version: "14.2"},
+		{Path: bin15, Version: "15.1"},
+	}, binaries)
+
+	examples := map[string]string{
+		"":     bin15,
+		"9.6":  bin9,
+		"10.1": bin12,
+		"12.8": bin12,
+		"13.0": custom,
+		"15.3": bin15,
+	}
+
+	for server, expected := range examples {
+		t.Run("server:"+server, func(t *testing.T) {
+			path, err := findClientBinary("pg_dump", server)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, path)
+		})
+	}
+
+	_, err = findClientBinary("pg_dump", "16.0")
+	assert.EqualError(t, err, "pg_dump version 15.1 not compatible with server version 16.0")
+
+	_, err = findClientBinary("pg_restore", "16.0")
+	assert.EqualError(t, err, "pg_restore command not found")
+
+	t.Run("dump validation", func(t *testing.T) {
+		dump := Dump{}
+		assert.NoError(t, dump.Validate("13"))
+		assert.Equal(t, custom, dump.command())
+	})
+}
+
+func TestBinaryPaths(t *testing.T) {
+	root := t.TempDir()
+	dump := writeFakeBinary(t, filepath.Join(root, "bin"), "pg_dump", "")
+	restore := writeFakeBinary(t, filepath.Join(root, "bin"), "pg_restore", "")
+
+	assert.Equal(t, []string{dump}, binaryPaths("pg_dump", filepath.Join(root, "bin")))
+	assert.Equal(t, []string{restore}, binaryPaths("pg_restore", " , "+filepath.Join(root, "b*")))
+	assert.Equal(t, []string{dump}, binaryPaths("pg_dump", dump+","+restore))
+	assert.Equal(t, []string{}, binaryPaths("psql", filepath.Join(root, "bin")+","+filepath.Join(root, "missing")))
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	BloatMethodEstimate    = "estimate"
+	BloatMethodPgstattuple = "pgstattuple"
+)
+
+var errPgstattupleMissing = errors.New("pgstattuple extension is not installed")
+
+var (
+	// Columns allowed for sorting of the bloat report
+	bloatSortColumns = map[string]bool{
+		"type":            true,
+		"schema_name":     true,
+		"table_name":      true,
+		"index_name":      true,
+		"real_size_bytes": true,
+		"bloat_bytes":     true,
+		"bloat_ratio":     true,
+	}
+)
+
+// BloatOptions contains parameters of the bloat report
+type BloatOptions struct {
+	Method     string // Estimation method: estimate (default) or pgstattuple
+	SortColumn string // Column to sort by, bloat_bytes by default
+	SortOrder  string // Sort direction (ASC, DESC), DESC by default
+}
+
+// Validate checks the bloat report options and fills in defaults
+func (opts *BloatOptions) Validate() error {
+	switch opts.Method {
+	case "":
+		opts.Method = BloatMethodEstimate
+	case BloatMethodEstimate, BloatMethodPgstattuple:
+	default:
+		return fmt.Errorf("invalid bloat method: %v", opts.Method)
+	}
+
+	if opts.SortColumn == "" {
+		opts.SortColumn = "bloat_bytes"
+	}
+	if !bloatSortColumns[opts.SortColumn] {
+		return fmt.Errorf("invalid sort column: %v", opts.SortColumn)
+	}
+
+	opts.SortOrder = strings.ToUpper(opts.SortOrder)
+	switch opts.SortOrder {
+	case "":
+		opts.SortOrder = "DESC"
+	case "ASC", "DESC":
+	default:
+		return fmt.Errorf("invalid sort order: %v", opts.SortOrder)
+	}
+
+	return nil
+}
+
+// Bloat returns estimated wasted space of tables and btree indexes based on catalog
+// statistics. The pgstattuple method gives exact numbers at the cost of scanning
+// every relation, so it's only used when requested explicitly.
+func (client *Client) Bloat(opts BloatOptions) (*Result, error) {
+	if client.serverType == cockroachType {
+		return nil, errors.New("bloat report is not supported on CockroachDB")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opts.Method == BloatMethodPgstattuple {
+		// LATERAL joins are available since 9.3
+		if major, minor := getMajorMinorVersion(client.serverVersion); major < 9 || (major == 9 && minor < 3) {
+			return nil, fmt.Errorf("pgstattuple method is not supported on PostgreSQL %v", client.serverVersion)
+		}
+
+		installed, err := client.hasExtension("pgstattuple")
+		if err != nil {
+			return nil, err
+		}
+		if !installed {
+			return nil, errPgstattupleMissing
+		}
+	}
+
+	return client.query(bloatQuery(opts.Method, opts))
+}
+
+func bloatQuery(method string, opts BloatOptions) string {
+	source := statements.BloatEstimate
+	if method == BloatMethodPgstattuple {
+		source = statements.BloatPgstattuple
+	}
+
+	return fmt.Sprintf(`SELECT
+  type,
+  schema_name,
+  table_name,
+  index_name,
+  pg_size_pretty(real_size_bytes) AS real_size,
+  real_size_bytes,
+  pg_size_pretty(bloat_bytes) AS bloat_size,
+  bloat_bytes,
+  ROUND(100.0 * bloat_bytes / NULLIF(real_size_bytes, 0), 2) AS bloat_ratio,
+  '%s' AS method
+FROM (%s) bloat
+ORDER BY %s %s NULLS LAST, schema_name, table_name, index_name`,
+		method, source, opts.SortColumn, opts.SortOrder,
+	)
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloatOptionsValidate(t *testing.T) {
+	opts := BloatOptions{}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, BloatOptions{Method: "estimate", SortColumn: "bloat_bytes", SortOrder: "DESC"}, opts)
+
+	opts = BloatOptions{Method: "pgstattuple", SortColumn: "bloat_ratio", SortOrder: "asc"}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, "ASC", opts.SortOrder)
+
+	examples := map[string]BloatOptions{
+		"invalid bloat method: exact":        {Method: "exact"},
+		"invalid bloat method: auto":         {Method: "auto"},
+		"invalid sort column: oid":           {SortColumn: "oid"},
+		"invalid sort order: DESC; SELECT 1": {SortOrder: "desc; select 1"},
+	}
+	for expected, opts := range examples {
+		assert.EqualError(t, opts.Validate(), expected)
+	}
+}
+
+func TestBloatQuery(t *testing.T) {
+	opts := BloatOptions{SortColumn: "bloat_ratio", SortOrder: "ASC"}
+
+	query := bloatQuery(BloatMethodEstimate, opts)
+	assert.Contains(t, query, "'estimate' AS method")
+	assert.Contains(t, query, "ORDER BY bloat_ratio ASC NULLS LAST")
+	assert.NotContains(t, query, "pgstattuple(")
+
+	query = bloatQuery(BloatMethodPgstattuple, opts)
+	assert.Contains(t, query, "'pgstattuple' AS method")
+	assert.Contains(t, query, "pgstattuple(")
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -22,6 +24,15 @@ import (
 	"github.com/sosedoff/pgweb/pkg/statements"
 )
 
+// Number of rows received between progress reports of StreamQuery
+const streamProgressRows = 1000
+
+// StreamLimits restricts the result size of StreamQuery, zero values are not limited
+type StreamLimits struct {
+	MaxRows  int   // Maximum number of rows
+	MaxBytes int64 // Maximum approximate size of rows in bytes
+}
+
 var (
 	regexErrAuthFailed        = regexp.MustCompile(`(authentication failed|role "(.*)" does not exist)`)
 	regexErrConnectionRefused = regexp.MustCompile(`(connection|actively) refused`)
@@ -32,6 +43,8 @@ var (
 	ErrAuthFailed        = errors.New("authentication failed")
 	ErrConnectionRefused = errors.New("connection refused")
 	ErrDatabaseNotExist  = errors.New("database does not exist")
+
+	errNotConnected = errors.New("not connected")
 )
 
 type Client struct {
@@ -39,13 +52,19 @@ type Client struct {
 	tunnel           *Tunnel
 	serverVersion    string
 	serverType       string
-	lastQueryTime    time.Time        // 上次查询时间
-	queryTimeout     time.Duration    // 查询超时配置
-	readonly         bool             // 只读状态标志位
-	closed           bool             // 关闭状态标志位
-	External         bool             `json:"external"`
-	History          []history.Record `json:"history"`
-	ConnectionString string           `json:"connection_string"`
+	lastQueryTime    time.Time                // 上次查询时间
+	queryTimeout     time.Duration            // 查询超时配置
+	readonly         bool                     // 只读状态标志位
+	closed           bool                     // 关闭状态标志位
+	profiles         map[string]*TableProfile // 表数据分析结果缓存
+	profilesMu       sync.Mutex               // 表数据分析结果缓存锁
+	listener         *Listener                // LISTEN / NOTIFY 专用连接
+	listenerMu       sync.Mutex               // LISTEN / NOTIFY 专用连接锁
+	listenerClosed   bool                     // 客户端已关闭，不再创建 LISTEN / NOTIFY 连接，由 listenerMu 保护
+	historyMu        sync.Mutex               // 查询历史锁
+	External         bool                     `json:"external"`
+	History          []history.Record         `json:"history"`
+	ConnectionString string                   `json:"connection_string"`
 }
 
 func getSchemaAndTable(str string) (string, string) {
@@ -310,6 +329,49 @@ func (client *Client) Function(id string) (*Result, error) {
 	return client.query(statements.Function, id)
 }
 
+// 获取分区表的分区键及分区
+func (client *Client) PartitionedTable(id string) (*Result, error) {
+	if major, _ := getMajorMinorVersion(client.serverVersion); major < 10 {
+		return nil, fmt.Errorf("partitioned tables are not supported on PostgreSQL %v", client.serverVersion)
+	}
+	return client.query(statements.PartitionedTable, id)
+}
+
+// 获取外部表
+func (client *Client) ForeignTable(id string) (*Result, error) {
+	return client.query(statements.ForeignTable, id)
+}
+
+// 获取触发器
+func (client *Client) Trigger(id string) (*Result, error) {
+	return client.query(statements.Trigger, id)
+}
+
+// 获取自定义类型
+func (client *Client) Type(id string) (*Result, error) {
+	return client.query(statements.Type, id)
+}
+
+// 获取扩展
+func (client *Client) Extension(id string) (*Result, error) {
+	return client.query(statements.Extension, id)
+}
+
+// Event triggers are database-wide objects, so they are not included in objects list
+// 获取事件触发器
+func (client *Client) EventTriggers() (*Result, error) {
+	major, minor := getMajorMinorVersion(client.serverVersion)
+	if major < 9 || (major == 9 && minor < 3) {
+		return nil, fmt.Errorf("event triggers are not supported on PostgreSQL %v", client.serverVersion)
+	}
+	return client.query(statements.EventTriggers)
+}
+
+// 获取事件触发器定义
+func (client *Client) EventTrigger(id string) (*Result, error) {
+	return client.query(statements.EventTrigger, id)
+}
+
 // 获取表记录
 func (client *Client) TableRows(table string, opts RowsOptions) (*Result, error) {
 	schema, table := getSchemaAndTable(table)
@@ -413,6 +475,43 @@ func (client *Client) TablesStats() (*Result, error) {
 	return client.query(statements.TablesStats)
 }
 
+// TableSize contains sizes of the table at the time of the query
+type TableSize struct {
+	Schema        string `json:"schema" db:"schema_name"`
+	Name          string `json:"name" db:"table_name"`
+	TableBytes    int64  `json:"table_bytes" db:"table_bytes"`
+	IndexBytes    int64  `json:"index_bytes" db:"index_bytes"`
+	TotalBytes    int64  `json:"total_bytes" db:"total_bytes"`
+	EstimatedRows int64  `json:"estimated_rows" db:"estimated_rows"`
+}
+
+// Returns table, index and total sizes along with estimated rows of all user tables
+// 获取所有用户表的大小及估算行数
+func (client *Client) TableSizes() ([]TableSize, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	sizes := []TableSize{}
+	if err := client.db.SelectContext(ctx, &sizes, statements.TableSizes); err != nil {
+		return nil, err
+	}
+
+	return sizes, nil
+}
+
+// Returns unused, duplicate, redundant and invalid indexes along with foreign keys without indexes
+// 获取索引健康报告
+func (client *Client) IndexHealth() (*Result, error) {
+	if client.serverType == cockroachType {
+		return nil, errors.New("index health report is not supported on CockroachDB")
+	}
+	return client.query(statements.IndexHealth)
+}
+
 // 获取服务器端设置
 func (client *Client) ServerSettings() (*Result, error) {
 	return client.query(statements.Settings)
@@ -439,8 +538,8 @@ func (client *Client) Query(query string) (*Result, error) {
 	res, err := client.query(query)
 
 	// Save history records only if query did not fail
-	if err == nil && !client.hasHistoryRecord(query) {
-		client.History = append(client.History, history.NewRecord(query))
+	if err == nil {
+		client.addHistoryRecord(query)
 	}
 
 	return res, err
@@ -460,6 +559,11 @@ func (client *Client) SetReadOnlyMode() error {
 	return nil
 }
 
+// IsReadOnly returns true if the connection is restricted to read-only queries
+func (client *Client) IsReadOnly() bool {
+	return command.Opts.ReadOnly || client.readonly
+}
+
 func (client *Client) ServerVersionInfo() string {
 	return fmt.Sprintf("%s %s", client.serverType, client.serverVersion)
 }
@@ -509,6 +613,22 @@ func (client *Client) exec(query string, args ...interface{}) (*Result, error) {
 	return &result, nil
 }
 
+// checkReadOnly enforces the read-only transaction mode and rejects restricted queries
+func (client *Client) checkReadOnly(query string) error {
+	if !client.IsReadOnly() {
+		return nil
+	}
+
+	if err := client.SetReadOnlyMode(); err != nil {
+		return err
+	}
+	if containsRestrictedKeywords(query) {
+		return errors.New("query contains keywords not allowed in read-only mode")
+	}
+
+	return nil
+}
+
 // 执行 SQL 查询
 func (client *Client) query(query string, args ...interface{}) (*Result, error) {
 	if client.db == nil {
@@ -523,13 +643,8 @@ func (client *Client) query(query string, args ...interface{}) (*Result, error)
 
 	// We're going to force-set transaction mode on every query.
 	// This is needed so that default mode could not be changed by user.
-	if command.Opts.ReadOnly || client.readonly {
-		if err := client.SetReadOnlyMode(); err != nil {
-			return nil, err
-		}
-		if containsRestrictedKeywords(query) {
-			return nil, errors.New("query contains keywords not allowed in read-only mode")
-		}
+	if err := client.checkReadOnly(query); err != nil {
+		return nil, err
 	}
 
 	// 获取首个关键词，并进行小写处理
@@ -613,6 +728,145 @@ func (client *Client) query(query string, args ...interface{}) (*Result, error)
 	return &result, nil
 }
 
+// StreamQuery runs the query within the context, collecting rows into the result and
+// reporting the number of received rows. The query fails when the result exceeds the
+// limits. Successful queries are saved to the history just like with Query.
+// 在后台任务中执行查询，并上报已接收的行数
+func (client *Client) StreamQuery(ctx context.Context, query string, limits StreamLimits, progress func(rows int)) (*Result, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+
+	defer func() {
+		client.lastQueryTime = time.Now().UTC()
+	}()
+
+	if err := client.checkReadOnly(query); err != nil {
+		return nil, err
+	}
+
+	if client.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.queryTimeout)
+		defer cancel()
+	}
+
+	queryStart := time.Now()
+	rows, err := client.db.QueryxContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if cols == nil {
+		cols = []string{}
+	}
+
+	result := &Result{
+		Columns: cols,
+		Rows:    []Row{},
+	}
+
+	var size int64
+	reported := -1
+
+	for rows.Next() {
+		if limits.MaxRows > 0 && len(result.Rows) >= limits.MaxRows {
+			return nil, fmt.Errorf("query returned more than %d rows", limits.MaxRows)
+		}
+
+		obj, err := rows.SliceScan()
+		if err != nil {
+			return nil, err
+		}
+		for i, item := range obj {
+			if b, ok := item.([]byte); ok {
+				obj[i] = string(b)
+			}
+		}
+		result.Rows = append(result.Rows, obj)
+
+		size += Row(obj).Size()
+		if limits.MaxBytes > 0 && size > limits.MaxBytes {
+			return nil, fmt.Errorf("query result is larger than %d bytes", limits.MaxBytes)
+		}
+
+		if progress != nil && len(result.Rows)%streamProgressRows == 0 {
+			progress(len(result.Rows))
+			reported = len(result.Rows)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	queryFinish := time.Now()
+
+	result.Stats = &ResultStats{
+		ColumnsCount:    len(cols),
+		RowsCount:       len(result.Rows),
+		QueryStartTime:  queryStart.UTC(),
+		QueryFinishTime: queryFinish.UTC(),
+		QueryDuration:   queryFinish.Sub(queryStart).Milliseconds(),
+	}
+	result.PostProcess()
+
+	if progress != nil && reported != len(result.Rows) {
+		progress(len(result.Rows))
+	}
+
+	client.addHistoryRecord(query)
+
+	return result, nil
+}
+
+// streamRows runs the query and calls fn for every row without buffering the result.
+// Column types are passed along so that callers could format the values.
+func (client *Client) streamRows(ctx context.Context, query string, fn func(columns []*sql.ColumnType, row []interface{}) error) error {
+	if client.db == nil {
+		return errNotConnected
+	}
+
+	defer func() {
+		client.lastQueryTime = time.Now().UTC()
+	}()
+
+	if err := client.checkReadOnly(query); err != nil {
+		return err
+	}
+
+	rows, err := client.db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		row := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range row {
+			ptrs[i] = &row[i]
+		}
+
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		if err := fn(columns, row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 // Close database connection
 // 关闭数据库连接
 func (client *Client) Close() error {
@@ -626,6 +880,8 @@ func (client *Client) Close() error {
 		client.tunnel = nil
 	}()
 
+	client.closeListener()
+
 	if client.tunnel != nil {
 		client.tunnel.Close()
 	}
@@ -656,8 +912,8 @@ func (client *Client) IsIdle() bool {
 }
 
 // Fetch all rows as strings for a single column
-func (client *Client) fetchRows(q string) ([]string, error) {
-	res, err := client.query(q)
+func (client *Client) fetchRows(q string, args ...interface{}) ([]string, error) {
+	res, err := client.query(q, args...)
 
 	if err != nil {
 		return nil, err
@@ -673,6 +929,26 @@ func (client *Client) fetchRows(q string) ([]string, error) {
 	return results, nil
 }
 
+// 记录查询历史，查询任务在后台执行，因此需要加锁
+func (client *Client) addHistoryRecord(query string) {
+	client.historyMu.Lock()
+	defer client.historyMu.Unlock()
+
+	if !client.hasHistoryRecord(query) {
+		client.History = append(client.History, history.NewRecord(query))
+	}
+}
+
+// HistoryRecords returns a copy of the query history
+func (client *Client) HistoryRecords() []history.Record {
+	client.historyMu.Lock()
+	defer client.historyMu.Unlock()
+
+	records := make([]history.Record, len(client.History))
+	copy(records, client.History)
+	return records
+}
+
 // 检查是否已经记录过了，已记录则不再重复记录
 func (client *Client) hasHistoryRecord(query string) bool {
 	result := false
@@ -687,6 +963,15 @@ func (client *Client) hasHistoryRecord(query string) bool {
 	return result
 }
 
+// hasExtension returns true if the extension is installed in the current database
+func (client *Client) hasExtension(name string) (bool, error) {
+	names, err := client.fetchRows("SELECT extname::text FROM pg_extension WHERE extname = $1", name)
+	if err != nil {
+		return false, err
+	}
+	return len(names) > 0, nil
+}
+
 type ConnContext struct {
 	Host     string
 	User     string
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -306,6 +307,27 @@ func testActivity(t *testing.T) {
 	assertMatches(t, expected, res.Columns)
 }
 
+func testSignalBackend(t *testing.T) {
+	_, err := testClient.SignalBackend(1, "kill")
+	assert.EqualError(t, err, "invalid backend action: kill")
+
+	_, err = testClient.SignalBackend(0, BackendActionCancel)
+	assert.EqualError(t, err, "invalid pid: 0")
+
+	// Postgres does not run any backends with pid 1
+	res, err := testClient.SignalBackend(1, BackendActionCancel)
+	assert.NoError(t, err)
+	assert.False(t, res.Delivered)
+	assert.True(t, res.Permitted)
+	assert.Equal(t, "backend process not found", res.Message)
+}
+
+func testBlockingTree(t *testing.T) {
+	tree, err := testClient.BlockingTree()
+	assert.NoError(t, err)
+	assert.Equal(t, []*BlockingNode{}, tree)
+}
+
 func testDatabases(t *testing.T) {
 	res, err := testClient.Databases()
 	assert.NoError(t, err)
@@ -377,7 +399,7 @@ func testObjects(t *testing.T) {
 	}
 
 	assert.NoError(t, err)
-	assert.Equal(t, []string{"oid", "schema", "name", "type", "owner", "comment"}, res.Columns)
+	assert.Equal(t, []string{"oid", "schema", "name", "type", "owner", "comment", "parent"}, res.Columns)
 	assert.Equal(t, []string{"public"}, mapKeys(objects))
 	assert.Equal(t, tables, objectNames(objects["public"].Tables))
 	assertMatches(t, functions, objectNames(objects["public"].Functions))
@@ -392,6 +414,30 @@ func testObjects(t *testing.T) {
 	}
 }
 
+func testObjectDetails(t *testing.T) {
+	res, err := testClient.Objects()
+	assert.NoError(t, err)
+
+	var trigger *Object
+	for _, obj := range ObjectsFromResult(res)["public"].Triggers {
+		if obj.Name == "check_shipment" {
+			trigger = &obj
+		}
+	}
+	if assert.NotNil(t, trigger) {
+		assert.Equal(t, "shipments", trigger.Table)
+
+		result, err := testClient.Trigger(trigger.OID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result.Rows))
+		assert.Contains(t, result.Columns, "definition")
+	}
+
+	result, err := testClient.EventTriggers()
+	assert.NoError(t, err)
+	assert.Equal(t, "oid", result.Columns[0])
+}
+
 func testTable(t *testing.T) {
 	columns := []string{
 		"column_name",
@@ -519,6 +565,60 @@ func testQuery(t *testing.T) {
 	})
 }
 
+func testStreamQuery(t *testing.T) {
+	t.Run("basic query", func(t *testing.T) {
+		progress := []int{}
+		res, err := testClient.StreamQuery(context.Background(), "SELECT generate_series(1, 2500) AS id, 'book' AS title", StreamLimits{}, func(rows int) {
+			progress = append(progress, rows)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"id", "title"}, res.Columns)
+		assert.Equal(t, 2500, len(res.Rows))
+		assert.Equal(t, Row{int64(1), "book"}, res.Rows[0])
+		assert.Equal(t, 2500, res.Stats.RowsCount)
+		assert.Equal(t, []int{1000, 2000, 2500}, progress)
+	})
+
+	t.Run("progress of full batches", func(t *testing.T) {
+		progress := []int{}
+		_, err := testClient.StreamQuery(context.Background(), "SELECT generate_series(1, 2000)", StreamLimits{}, func(rows int) {
+			progress = append(progress, rows)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1000, 2000}, progress)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		query := "SELECT 'stream history' AS name"
+		_, err := testClient.StreamQuery(context.Background(), query, StreamLimits{}, nil)
+		assert.NoError(t, err)
+
+		records := testClient.HistoryRecords()
+		assert.Equal(t, query, records[len(records)-1].Query)
+	})
+
+	t.Run("row limit", func(t *testing.T) {
+		res, err := testClient.StreamQuery(context.Background(), "SELECT generate_series(1, 20)", StreamLimits{MaxRows: 10}, nil)
+		assert.EqualError(t, err, "query returned more than 10 rows")
+		assert.Nil(t, res)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		res, err := testClient.StreamQuery(context.Background(), "SELECT repeat('a', 1000) FROM generate_series(1, 20)", StreamLimits{MaxBytes: 10000}, nil)
+		assert.EqualError(t, err, "query result is larger than 10000 bytes")
+		assert.Nil(t, res)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		res, err := testClient.StreamQuery(ctx, "SELECT pg_sleep(1)", StreamLimits{}, nil)
+		assert.Equal(t, "pq: canceling statement due to user request", err.Error())
+		assert.Nil(t, res)
+	})
+}
+
 func testUpdateQuery(t *testing.T) {
 	t.Run("updating data", func(t *testing.T) {
 		// Add new row
@@ -710,6 +810,212 @@ func testTablesStats(t *testing.T) {
 	assert.Equal(t, columns, result.Columns)
 }
 
+func testTableSizes(t *testing.T) {
+	sizes, err := testClient.TableSizes()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sizes)
+
+	for _, size := range sizes {
+		if size.Schema == "public" && size.Name == "books" {
+			assert.True(t, size.TotalBytes >= size.TableBytes+size.IndexBytes)
+			return
+		}
+	}
+	t.Error("public.books size is missing")
+}
+
+func testIndexHealth(t *testing.T) {
+	columns := []string{
+		"issue",
+		"schema_name",
+		"table_name",
+		"index_name",
+		"index_size",
+		"index_size_bytes",
+		"details",
+	}
+
+	result, err := testClient.IndexHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, columns, result.Columns)
+
+	for _, row := range result.Rows {
+		assert.Contains(t, []string{"unused", "invalid", "duplicate", "redundant", "missing_fk_index"}, row[0])
+	}
+}
+
+func testBloat(t *testing.T) {
+	columns := []string{
+		"type",
+		"schema_name",
+		"table_name",
+		"index_name",
+		"real_size",
+		"real_size_bytes",
+		"bloat_size",
+		"bloat_bytes",
+		"bloat_ratio",
+		"method",
+	}
+
+	result, err := testClient.Bloat(BloatOptions{Method: BloatMethodEstimate, SortColumn: "table_name", SortOrder: "asc"})
+	assert.NoError(t, err)
+	assert.Equal(t, columns, result.Columns)
+	assert.NotEmpty(t, result.Rows)
+
+	_, err = testClient.Bloat(BloatOptions{SortColumn: "1; DROP TABLE books"})
+	assert.EqualError(t, err, "invalid sort column: 1; DROP TABLE books")
+
+	// Extension is not installed in the test database by default
+	result, err = testClient.Bloat(BloatOptions{Method: BloatMethodPgstattuple})
+	if err != nil {
+		assert.Equal(t, errPgstattupleMissing, err)
+	} else {
+		assert.Equal(t, columns, result.Columns)
+	}
+}
+
+func testStatStatements(t *testing.T) {
+	// Extension is not installed in the test database by default
+	result, err := testClient.StatStatements(StatementsOptions{})
+	if err != nil {
+		assert.Equal(t, errStatStatementsMissing, err)
+		return
+	}
+	assert.Equal(t, "queryid", result.Columns[0])
+}
+
+func testMaintenance(t *testing.T) {
+	result, err := testClient.Maintenance()
+	assert.NoError(t, err)
+	assert.Equal(t, "schema_name", result.Columns[0])
+	assert.Equal(t, "wraparound_risk", result.Columns[len(result.Columns)-1])
+
+	res, err := testClient.RunMaintenance(context.Background(), "books", MaintenanceAnalyze)
+	assert.NoError(t, err)
+	assert.Equal(t, "public.books", res.Table)
+	assert.Equal(t, MaintenanceAnalyze, res.Action)
+}
+
+func testReplication(t *testing.T) {
+	status, err := testClient.Replication()
+	assert.NoError(t, err)
+	assert.Equal(t, ReplicationRolePrimary, status.Role)
+	assert.Nil(t, status.Receiver)
+	assert.Equal(t, "pid", status.Standbys.Columns[0])
+	assert.Contains(t, status.Standbys.Columns, "replay_lag_bytes")
+
+	if status.Slots != nil {
+		assert.Contains(t, status.Slots.Columns, "retained_wal_bytes")
+	}
+}
+
+func testPrivileges(t *testing.T) {
+	result, err := testClient.Roles()
+	assert.NoError(t, err)
+	assert.Equal(t, "role_name", result.Columns[0])
+	assert.Contains(t, result.Columns, "all_member_of")
+
+	result, err = testClient.Privileges(PrivilegeObjectTable, "books")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"role_name", "privilege_type", "is_grantable", "grantor", "granted_via"}, result.Columns)
+	assert.NotEmpty(t, result.Rows)
+
+	result, err = testClient.Privileges(PrivilegeObjectSchema, "public")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Rows)
+
+	result, err = testClient.DefaultPrivileges()
+	assert.NoError(t, err)
+	assert.Equal(t, "owner", result.Columns[0])
+}
+
+func testDependencies(t *testing.T) {
+	tree, err := testClient.Dependencies(DependencyOptions{Type: DependencyObjectColumn, Object: "stock", Column: "retail"})
+	assert.NoError(t, err)
+	assert.Equal(t, "column retail of table stock", tree.Object)
+
+	names := []string{}
+	for _, node := range tree.Dependencies {
+		names = append(names, node.Name)
+	}
+	assert.Contains(t, names, "view stock_view")
+
+	tree, err = testClient.Dependencies(DependencyOptions{Type: DependencyObjectTable, Object: "stock_view", Direction: DependencyDirectionDependsOn})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tree.Dependencies)
+
+	_, err = testClient.Dependencies(DependencyOptions{Type: DependencyObjectColumn, Object: "stock", Column: "missing"})
+	assert.EqualError(t, err, "column not found: stock.missing")
+}
+
+func testColumnProfile(t *testing.T) {
+	result, err := testClient.ColumnStats("books")
+	assert.NoError(t, err)
+	assert.Equal(t, "column_name", result.Columns[0])
+	assert.Equal(t, 4, len(result.Rows))
+
+	assert.Nil(t, testClient.TableProfile("books"))
+
+	progress := []ProfileProgress{}
+	profile, err := testClient.ProfileTable(context.Background(), "books", ProfileOptions{SamplePercent: 100}, func(p ProfileProgress) {
+		progress = append(progress, p)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "public.books", profile.Table)
+	assert.Equal(t, 4, len(profile.Columns))
+	assert.Equal(t, "id", profile.Columns[0].Name)
+	assert.NotZero(t, profile.Columns[0].Rows)
+	assert.Equal(t, int64(0), profile.Columns[0].Nulls)
+	assert.Equal(t, profile.Columns[0].Rows, *profile.Columns[0].Distinct)
+	assert.Equal(t, 4, len(progress))
+	assert.Equal(t, profile, testClient.TableProfile("public.books"))
+}
+
+func testSearch(t *testing.T) {
+	result, err := testClient.Search(SearchOptions{Query: "books"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"type", "schema_name", "parent_name", "name", "comment", "matched", "rank"}, result.Columns)
+	assert.Equal(t, "table", result.Rows[0][0])
+	assert.Equal(t, "books", result.Rows[0][3])
+
+	result, err = testClient.Search(SearchOptions{Query: "author_id", Types: []string{"column"}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Rows)
+	for _, row := range result.Rows {
+		assert.Equal(t, "column", row[0])
+	}
+
+	// Underscore must not match any character
+	result, err = testClient.Search(SearchOptions{Query: "author_i_"})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Rows)
+}
+
+func testSearchValue(t *testing.T) {
+	progress := []ValueSearchProgress{}
+	result, err := testClient.SearchValue(context.Background(), ValueSearchOptions{Value: "The Shining", Schemas: []string{"public"}}, func(p ValueSearchProgress) {
+		progress = append(progress, p)
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Skipped)
+	assert.Equal(t, result.Tables, len(progress))
+	assert.Contains(t, result.Hits, ValueSearchHit{
+		Table:   "public.books",
+		Columns: []string{"title"},
+		RowKey:  map[string]string{"id": "7808"},
+	})
+
+	result, err = testClient.SearchValue(context.Background(), ValueSearchOptions{Value: "shin", Match: ValueMatchContains, Limit: 1}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Hits))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = testClient.SearchValue(ctx, ValueSearchOptions{Value: "The Shining"}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func testConnContext(t *testing.T) {
 	result, err := testClient.GetConnContext()
 	assert.NoError(t, err)
@@ -719,6 +1025,26 @@ func testConnContext(t *testing.T) {
 	assert.Equal(t, "default", result.Mode)
 }
 
+func testNotifications(t *testing.T) {
+	sub, err := testClient.Subscribe([]string{"pgweb_test"})
+	assert.NoError(t, err)
+
+	_, err = testClient.query("SELECT pg_notify('pgweb_test', 'hello')")
+	assert.NoError(t, err)
+
+	select {
+	case n := <-sub.C:
+		assert.Equal(t, "pgweb_test", n.Channel)
+		assert.Equal(t, "hello", n.Payload)
+	case <-time.After(5 * time.Second):
+		t.Error("notification was not received")
+	}
+
+	sub.Close()
+	_, ok := <-sub.C
+	assert.False(t, ok)
+}
+
 func testServerSettings(t *testing.T) {
 	expectedColumns := []string{
 		"name",
@@ -745,6 +1071,24 @@ func testServerSettings(t *testing.T) {
 	assert.Equal(t, expectedColumns, result.Columns)
 }
 
+func testSettings(t *testing.T) {
+	categories, err := testClient.Settings(SettingsOptions{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, categories)
+
+	categories, err = testClient.Settings(SettingsOptions{Source: SettingSourceDefault})
+	assert.NoError(t, err)
+	for _, category := range categories {
+		for _, setting := range category.Settings {
+			assert.Equal(t, "default", setting.Source)
+			assert.False(t, setting.Changed)
+		}
+	}
+
+	assert.EqualError(t, testClient.AlterSystemSet("missing_setting", "1"), "unknown setting: missing_setting")
+	assert.EqualError(t, testClient.AlterSystemReset("server_version"), "setting server_version can't be changed")
+}
+
 func TestAll(t *testing.T) {
 	if onWindows() {
 		t.Log("Unit testing on Windows platform is not supported.")
@@ -762,9 +1106,12 @@ func TestAll(t *testing.T) {
 	testTest(t)
 	testInfo(t)
 	testActivity(t)
+	testSignalBackend(t)
+	testBlockingTree(t)
 	testDatabases(t)
 	testSchemas(t)
 	testObjects(t)
+	testObjectDetails(t)
 	testTable(t)
 	testTableRows(t)
 	testTableInfo(t)
@@ -775,6 +1122,7 @@ func TestAll(t *testing.T) {
 	testTableConstraints(t)
 	testTableNameWithCamelCase(t)
 	testQuery(t)
+	testStreamQuery(t)
 	testUpdateQuery(t)
 	testTableRowsOrderEscape(t)
 	testFunctions(t)
@@ -782,9 +1130,23 @@ func TestAll(t *testing.T) {
 	testHistory(t)
 	testReadOnlyMode(t)
 	testDumpExport(t)
+	testNativeExport(t)
 	testTablesStats(t)
+	testTableSizes(t)
+	testIndexHealth(t)
+	testBloat(t)
+	testStatStatements(t)
+	testMaintenance(t)
+	testReplication(t)
+	testPrivileges(t)
+	testDependencies(t)
+	testColumnProfile(t)
+	testSearch(t)
+	testSearchValue(t)
 	testConnContext(t)
+	testNotifications(t)
 	testServerSettings(t)
+	testSettings(t)
 
 	teardownClient()
 	teardown(t, true)
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// Default layout of time values in CSV output
+	defaultCSVTimeFormat = "2006-01-02 15:04:05"
+)
+
+var (
+	// Named time formats accepted in CSV options
+	csvTimeFormats = map[string]string{
+		"default":     defaultCSVTimeFormat,
+		"rfc3339":     time.RFC3339Nano,
+		"postgres":    "2006-01-02 15:04:05.999999Z07:00",
+		"date":        time.DateOnly,
+		"datetime_ms": "2006-01-02 15:04:05.000",
+	}
+
+	// UTF-8 byte order mark, helps Excel to detect the file encoding
+	utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+)
+
+// CSVOptions contains formatting options of the CSV output.
+// Zero value produces comma-separated output with a header and empty NULL values.
+type CSVOptions struct {
+	Delimiter  rune   // Field delimiter, comma by default
+	QuoteAll   bool   // Quote every field
+	SkipHeader bool   // Do not write the column names
+	Null       string // Representation of NULL values, text equal to it is always quoted
+	BOM        bool   // Start the output with UTF-8 byte order mark
+	TimeFormat string // Named format (default, rfc3339, postgres, date, datetime_ms, unix) or Go layout
+	Timezone   string // Timezone of time values, values are kept as returned by the server when empty
+
+	location *time.Location
+}
+
+// Validate checks the CSV options and fills in defaults
+func (opts *CSVOptions) Validate() error {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	if opts.Delimiter == '"' || opts.Delimiter == '\r' || opts.Delimiter == '\n' || opts.Delimiter == utf8.RuneError {
+		return fmt.Errorf("invalid delimiter: %q", opts.Delimiter)
+	}
+	if strings.ContainsAny(opts.Null, "\r\n") {
+		return errors.New("null marker can't contain line breaks")
+	}
+
+	if layout, ok := csvTimeFormats[opts.TimeFormat]; ok {
+		opts.TimeFormat = layout
+	}
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = defaultCSVTimeFormat
+	}
+	if opts.TimeFormat != "unix" && time.Unix(0, 0).UTC().Format(opts.TimeFormat) == opts.TimeFormat {
+		return fmt.Errorf("invalid time format: %v", opts.TimeFormat)
+	}
+
+	if opts.Timezone != "" {
+		location, err := time.LoadLocation(opts.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %v", opts.Timezone)
+		}
+		opts.location = location
+	}
+
+	return nil
+}
+
+// WriteCSV writes the result in CSV format using the given options
+func (res *Result) WriteCSV(w io.Writer, opts CSVOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	writer := &csvWriter{
+		w:         bufio.NewWriter(w),
+		delimiter: opts.Delimiter,
+	}
+
+	if opts.BOM {
+		writer.w.Write(utf8BOM) //nolint
+	}
+
+	record := make([]string, len(res.Columns))
+	forceQuote := make([]bool, len(res.Columns))
+
+	if !opts.SkipHeader {
+		copy(record, res.Columns)
+		for i := range forceQuote {
+			forceQuote[i] = opts.QuoteAll
+		}
+		if err := writer.writeRecord(record, forceQuote); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range res.Rows {
+		for i := range record {
+			record[i] = ""
+			forceQuote[i] = false
+
+			if i >= len(row) {
+				continue
+			}
+
+			switch v := row[i].(type) {
+			case nil:
+				// NULL values are never quoted, so they could be told apart from text
+				record[i] = opts.Null
+				continue
+			case time.Time:
+				record[i] = formatCSVTime(v, opts)
+			default:
+				record[i] = fmt.Sprintf("%v", v)
+			}
+
+			forceQuote[i] = opts.QuoteAll || (opts.Null != "" && record[i] == opts.Null)
+		}
+
+		if err := writer.writeRecord(record, forceQuote); err != nil {
+			return err
+		}
+	}
+
+	return writer.w.Flush()
+}
+
+func formatCSVTime(val time.Time, opts CSVOptions) string {
+	if opts.location != nil {
+		val = val.In(opts.location)
+	}
+	if opts.TimeFormat == "unix" {
+		return strconv.FormatInt(val.Unix(), 10)
+	}
+	return val.Format(opts.TimeFormat)
+}
+
+// csvWriter writes CSV records the same way as encoding/csv does,
+// but allows quoting of individual fields
+type csvWriter struct {
+	w         *bufio.Writer
+	delimiter rune
+}
+
+func (cw *csvWriter) writeRecord(record []string, forceQuote []bool) error {
+	for i, field := range record {
+		if i > 0 {
+			cw.w.WriteRune(cw.delimiter) //nolint
+		}
+
+		if !forceQuote[i] && !cw.needsQuotes(field) {
+			cw.w.WriteString(field) //nolint
+			continue
+		}
+
+		cw.w.WriteByte('"')                                    //nolint
+		cw.w.WriteString(strings.ReplaceAll(field, `"`, `""`)) //nolint
+		cw.w.WriteByte('"')                                    //nolint
+	}
+
+	_, err := cw.w.WriteRune('\n')
+	return err
+}
+
+// needsQuotes follows the rules of encoding/csv writer
+func (cw *csvWriter) needsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` {
+		return true
+	}
+	if strings.ContainsRune(field, cw.delimiter) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	ts := time.Date(2023, 4, 5, 10, 20, 30, 123000000, time.UTC)
+
+	result := Result{
+		Columns: []string{"id", "name", "created_at"},
+		Rows: []Row{
+			{1, "NULL", ts},
+			{2, nil, nil},
+			{3, "", ts},
+			{4, "a;b", nil},
+		},
+	}
+
+	examples := []struct {
+		name     string
+		opts     CSVOptions
+		expected string
+	}{
+		{
+			name:     "defaults",
+			opts:     CSVOptions{},
+			expected: "id,name,created_at\n1,NULL,2023-04-05 10:20:30\n2,,\n3,,2023-04-05 10:20:30\n4,a;b,\n",
+		},
+		{
+			name:     "delimiter without header",
+			opts:     CSVOptions{Delimiter: ';', SkipHeader: true},
+			expected: "1;NULL;2023-04-05 10:20:30\n2;;\n3;;2023-04-05 10:20:30\n4;\"a;b\";\n",
+		},
+		{
+			name:     "null marker",
+			opts:     CSVOptions{Null: "NULL"},
+			expected: "id,name,created_at\n1,\"NULL\",2023-04-05 10:20:30\n2,NULL,NULL\n3,,2023-04-05 10:20:30\n4,a;b,NULL\n",
+		},
+		{
+			name:     "quote all",
+			opts:     CSVOptions{QuoteAll: true, SkipHeader: true, TimeFormat: "date"},
+			expected: "\"1\",\"NULL\",\"2023-04-05\"\n\"2\",,\n\"3\",\"\",\"2023-04-05\"\n\"4\",\"a;b\",\n",
+		},
+		{
+			name:     "time format and timezone",
+			opts:     CSVOptions{SkipHeader: true, TimeFormat: "rfc3339", Timezone: "Etc/GMT-3"},
+			expected: "1,NULL,2023-04-05T13:20:30.123+03:00\n2,,\n3,,2023-04-05T13:20:30.123+03:00\n4,a;b,\n",
+		},
+		{
+			name:     "unix time",
+			opts:     CSVOptions{SkipHeader: true, TimeFormat: "unix"},
+			expected: "1,NULL,1680690030\n2,,\n3,,1680690030\n4,a;b,\n",
+		},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			require.NoError(t, result.WriteCSV(buf, ex.opts))
+			assert.Equal(t, ex.expected, buf.String())
+		})
+	}
+
+	t.Run("bom", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, result.WriteCSV(buf, CSVOptions{BOM: true}))
+		assert.Equal(t, []byte{0xEF, 0xBB, 0xBF, 'i', 'd'}, buf.Bytes()[0:5])
+	})
+}
+
+func TestCSVOptionsValidate(t *testing.T) {
+	examples := []struct {
+		opts CSVOptions
+		err  string
+	}{
+		{opts: CSVOptions{Delimiter: '"'}, err: `invalid delimiter: '"'`},
+		{opts: CSVOptions{Delimiter: '\n'}, err: `invalid delimiter: '\n'`},
+		{opts: CSVOptions{Null: "\n"}, err: "null marker can't contain line breaks"},
+		{opts: CSVOptions{TimeFormat: "iso"}, err: "invalid time format: iso"},
+		{opts: CSVOptions{Timezone: "Mars/Olympus"}, err: "invalid timezone: Mars/Olympus"},
+		{opts: CSVOptions{TimeFormat: "02.01.2006"}},
+		{opts: CSVOptions{Timezone: "UTC"}},
+	}
+
+	for _, ex := range examples {
+		err := ex.opts.Validate()
+		if ex.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, ex.err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	DependencyDirectionDependents = "dependents" // Objects that depend on the selected object
+	DependencyDirectionDependsOn  = "depends_on" // Objects the selected object depends on
+
+	DependencyObjectTable    = "table"
+	DependencyObjectColumn   = "column"
+	DependencyObjectFunction = "function"
+	DependencyObjectType     = "type"
+)
+
+// DependencyOptions contains the object to explore dependencies of
+type DependencyOptions struct {
+	Type      string // table, column, function or type
+	Object    string // Table name for tables and columns, oid for functions and types
+	Column    string // Column name
+	Direction string // dependents or depends_on
+}
+
+// Validate checks the options and fills in defaults
+func (opts *DependencyOptions) Validate() error {
+	if opts.Direction == "" {
+		opts.Direction = DependencyDirectionDependents
+	}
+	if opts.Direction != DependencyDirectionDependents && opts.Direction != DependencyDirectionDependsOn {
+		return fmt.Errorf("invalid dependency direction: %v", opts.Direction)
+	}
+	if opts.Object == "" {
+		return errors.New("object name is required")
+	}
+
+	switch opts.Type {
+	case DependencyObjectTable:
+	case DependencyObjectColumn:
+		if opts.Column == "" {
+			return errors.New("column name is required")
+		}
+	case DependencyObjectFunction, DependencyObjectType:
+		if _, err := strconv.Atoi(opts.Object); err != nil {
+			return fmt.Errorf("invalid %v oid: %v", opts.Type, opts.Object)
+		}
+	default:
+		return fmt.Errorf("invalid object type: %v", opts.Type)
+	}
+
+	return nil
+}
+
+// DependencyObject is a database object found while walking pg_depend
+type DependencyObject struct {
+	ID             string `json:"id" db:"id"`
+	ParentID       string `json:"-" db:"parent_id"`
+	Kind           string `json:"kind" db:"kind"`
+	OID            string `json:"oid" db:"oid"`
+	Name           string `json:"name" db:"name"`                       // Object description
+	DependencyType string `json:"dependency_type" db:"dependency_type"` // normal or auto
+}
+
+// DependencyNode is an object in the dependency tree along with its own dependencies
+type DependencyNode struct {
+	DependencyObject
+	Dependencies []*DependencyNode `json:"dependencies"`
+}
+
+// DependencyTree contains dependencies of the object in the requested direction
+type DependencyTree struct {
+	Object       string            `json:"object"`
+	Direction    string            `json:"direction"`
+	Dependencies []*DependencyNode `json:"dependencies"`
+}
+
+// dependencyTargetQuery returns the system catalog of the object and the query
+// resolving its oid and sub-object id (column number)
+func dependencyTargetQuery(opts DependencyOptions) (string, string, []interface{}) {
+	switch opts.Type {
+	case DependencyObjectColumn:
+		return "pg_catalog.pg_class",
+			"SELECT a.attrelid, a.attnum FROM pg_catalog.pg_attribute a WHERE a.attrelid = $1::regclass AND a.attname = $2 AND a.attnum > 0 AND NOT a.attisdropped",
+			[]interface{}{quoteTable(opts.Object), opts.Column}
+	case DependencyObjectFunction:
+		return "pg_catalog.pg_proc",
+			"SELECT p.oid, 0 FROM pg_catalog.pg_proc p WHERE p.oid = $1::oid",
+			[]interface{}{opts.Object}
+	case DependencyObjectType:
+		return "pg_catalog.pg_type",
+			"SELECT t.oid, 0 FROM pg_catalog.pg_type t WHERE t.oid = $1::oid",
+			[]interface{}{opts.Object}
+	default:
+		return "pg_catalog.pg_class",
+			"SELECT $1::regclass::oid, 0",
+			[]interface{}{quoteTable(opts.Object)}
+	}
+}
+
+// Dependencies returns the recursive tree of objects depending on the table, column,
+// function or type, or the objects it depends on
+func (client *Client) Dependencies(opts DependencyOptions) (*DependencyTree, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return nil, errors.New("dependency explorer is not supported on CockroachDB")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	catalog, query, args := dependencyTargetQuery(opts)
+
+	var oid, subID int64
+	if err := client.db.QueryRowContext(ctx, query, args...).Scan(&oid, &subID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			name := opts.Object
+			if opts.Type == DependencyObjectColumn {
+				name += "." + opts.Column
+			}
+			return nil, fmt.Errorf("%v not found: %v", opts.Type, name)
+		}
+		return nil, err
+	}
+
+	tree := &DependencyTree{Direction: opts.Direction}
+
+	err := client.db.QueryRowContext(ctx, "SELECT pg_catalog.pg_describe_object($1::regclass, $2::oid, $3)", catalog, oid, subID).Scan(&tree.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []DependencyObject{}
+	if err := client.db.SelectContext(ctx, &objects, statements.Dependencies, catalog, oid, subID, opts.Direction); err != nil {
+		return nil, err
+	}
+
+	tree.Dependencies = buildDependencyTree(objects)
+	return tree, nil
+}
+
+// buildDependencyTree nests objects under their parents. An object reachable
+// through several paths appears under each of its parents.
+func buildDependencyTree(objects []DependencyObject) []*DependencyNode {
+	children := map[string][]DependencyObject{}
+	edges := map[[2]string]bool{}
+
+	for _, obj := range objects {
+		edge := [2]string{obj.ParentID, obj.ID}
+		if edges[edge] {
+			continue
+		}
+		edges[edge] = true
+		children[obj.ParentID] = append(children[obj.ParentID], obj)
+	}
+
+	path := map[string]bool{}
+
+	var build func(obj DependencyObject) *DependencyNode
+	build = func(obj DependencyObject) *DependencyNode {
+		node := &DependencyNode{DependencyObject: obj, Dependencies: []*DependencyNode{}}
+		path[obj.ID] = true
+
+		for _, child := range children[obj.ID] {
+			// Guard against dependency cycles
+			if path[child.ID] {
+				continue
+			}
+			node.Dependencies = append(node.Dependencies, build(child))
+		}
+
+		delete(path, obj.ID)
+		return node
+	}
+
+	roots := []*DependencyNode{}
+	for _, obj := range children[""] {
+		roots = append(roots, build(obj))
+	}
+
+	return roots
+}
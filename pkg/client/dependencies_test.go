@@ -0,0 +1,59 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyOptionsValidate(t *testing.T) {
+	opts := DependencyOptions{Type: DependencyObjectTable, Object: "books"}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, DependencyDirectionDependents, opts.Direction)
+
+	examples := []struct {
+		opts DependencyOptions
+		err  string
+	}{
+		{opts: DependencyOptions{Type: DependencyObjectTable}, err: "object name is required"},
+		{opts: DependencyOptions{Type: DependencyObjectColumn, Object: "books"}, err: "column name is required"},
+		{opts: DependencyOptions{Type: DependencyObjectFunction, Object: "now"}, err: "invalid function oid: now"},
+		{opts: DependencyOptions{Type: "index", Object: "books"}, err: "invalid object type: index"},
+		{opts: DependencyOptions{Type: DependencyObjectTable, Object: "books", Direction: "up"}, err: "invalid dependency direction: up"},
+		{opts: DependencyOptions{Type: DependencyObjectType, Object: "16384", Direction: DependencyDirectionDependsOn}},
+	}
+
+	for _, ex := range examples {
+		err := ex.opts.Validate()
+		if ex.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, ex.err)
+		}
+	}
+}
+
+func TestBuildDependencyTree(t *testing.T) {
+	objects := []DependencyObject{
+		{ID: "1259:10:0", ParentID: "", Kind: "view", Name: "view a"},
+		{ID: "1259:11:0", ParentID: "1259:10:0", Kind: "view", Name: "view b"},
+		{ID: "1259:11:0", ParentID: "1259:10:0", Kind: "view", Name: "view b"},
+		{ID: "1259:11:0", ParentID: "", Kind: "view", Name: "view b"},
+		{ID: "1259:10:0", ParentID: "1259:11:0", Kind: "view", Name: "view a"},
+		{ID: "2620:20:0", ParentID: "1259:11:0", Kind: "trigger", Name: "trigger t on view b"},
+	}
+
+	tree := buildDependencyTree(objects)
+	assert.Equal(t, 2, len(tree))
+
+	assert.Equal(t, "view a", tree[0].Name)
+	assert.Equal(t, 1, len(tree[0].Dependencies))
+	assert.Equal(t, "view b", tree[0].Dependencies[0].Name)
+	assert.Equal(t, 1, len(tree[0].Dependencies[0].Dependencies))
+	assert.Equal(t, "trigger", tree[0].Dependencies[0].Dependencies[0].Kind)
+
+	assert.Equal(t, "view b", tree[1].Name)
+	assert.Equal(t, 2, len(tree[1].Dependencies))
+	assert.Equal(t, "view a", tree[1].Dependencies[0].Name)
+	assert.Empty(t, tree[1].Dependencies[0].Dependencies)
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -10,6 +11,12 @@ import (
 	"strings"
 )
 
+const (
+	DumpFormatPlain  = "plain"
+	DumpFormatCustom = "custom"
+	DumpFormatTar    = "tar"
+)
+
 var (
 	unsupportedDumpOptions = []string{
 		"search_path",
@@ -18,32 +25,75 @@ var (
 
 // Dump represents a database dump
 type Dump struct {
-	Table string
+	Table          string   // Single table to dump, kept for compatibility
+	Format         string   // Output format: plain, custom or tar
+	SchemaOnly     bool     // Dump only the object definitions
+	DataOnly       bool     // Dump only the data
+	Tables         []string // Table patterns to include
+	ExcludeTables  []string // Table patterns to exclude
+	Schemas        []string // Schema patterns to include
+	ExcludeSchemas []string // Schema patterns to exclude
+	Inserts        bool     // Dump data as INSERT commands
+	ColumnInserts  bool     // Dump data as INSERT commands with column names
+	NoPrivileges   bool     // Skip dumping of access privileges
+
+	binary string // Path of the pg_dump binary selected for the server
 }
 
-// Validate checks availability and version of pg_dump CLI
+// Validate checks availability and version of pg_dump CLI and selects
+// the binary compatible with the server version
 func (d *Dump) Validate(serverVersion string) error {
-	out := bytes.NewBuffer(nil)
+	path, err := findClientBinary("pg_dump", serverVersion)
+	if err != nil {
+		return err
+	}
 
-	cmd := exec.Command("pg_dump", "--version")
-	cmd.Stdout = out
-	cmd.Stderr = out
+	d.binary = path
+	return nil
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("pg_dump command failed: %s", out.Bytes())
+// ValidateOptions checks the combination of dump options
+func (d *Dump) ValidateOptions() error {
+	switch d.Format {
+	case "", DumpFormatPlain, DumpFormatCustom, DumpFormatTar:
+	default:
+		return fmt.Errorf("invalid dump format: %v", d.Format)
+	}
+
+	if d.SchemaOnly && d.DataOnly {
+		return errors.New("schema-only and data-only options can't be used together")
+	}
+	if d.SchemaOnly && (d.Inserts || d.ColumnInserts) {
+		return errors.New("inserts options can't be used with schema-only dumps")
 	}
 
-	detected, dumpVersion := detectDumpVersion(out.String())
-	if detected && serverVersion != "" {
-		satisfied := checkVersionRequirement(dumpVersion, serverVersion)
-		if !satisfied {
-			return fmt.Errorf("pg_dump version %v not compatible with server version %v", dumpVersion, serverVersion)
+	patterns := [][]string{d.Tables, d.ExcludeTables, d.Schemas, d.ExcludeSchemas}
+	for _, list := range patterns {
+		for _, pattern := range list {
+			if strings.TrimSpace(pattern) == "" {
+				return errors.New("dump patterns can't be empty")
+			}
+			if strings.HasPrefix(pattern, "-") {
+				return fmt.Errorf("invalid dump pattern: %q", pattern)
+			}
 		}
 	}
 
 	return nil
 }
 
+// Extension returns the file extension of the dump output
+func (d *Dump) Extension() string {
+	switch d.Format {
+	case DumpFormatCustom:
+		return "dump"
+	case DumpFormatTar:
+		return "tar"
+	default:
+		return "sql.gz"
+	}
+}
+
 // Export streams the database dump to the specified writer
 func (d *Dump) Export(ctx context.Context, connstr string, writer io.Writer) error {
 	if str, err := removeUnsupportedOptions(connstr); err != nil {
@@ -52,20 +102,15 @@ func (d *Dump) Export(ctx context.Context, connstr string, writer io.Writer) err
 		connstr = str
 	}
 
-	opts := []string{
-		"--no-owner",      // skip restoration of object ownership in plain-text format
-		"--clean",         // clean (drop) database objects before recreating
-		"--compress", "6", // compression level for compressed formats
-	}
-
-	if d.Table != "" {
-		opts = append(opts, []string{"--table", d.Table}...)
+	opts, err := d.options()
+	if err != nil {
+		return err
 	}
 
 	opts = append(opts, connstr)
 	errOutput := bytes.NewBuffer(nil)
 
-	cmd := exec.CommandContext(ctx, "pg_dump", opts...)
+	cmd := exec.CommandContext(ctx, d.command(), opts...)
 	cmd.Stdout = writer
 	cmd.Stderr = errOutput
 
@@ -75,6 +120,73 @@ func (d *Dump) Export(ctx context.Context, connstr string, writer io.Writer) err
 	return nil
 }
 
+func (d *Dump) command() string {
+	if d.binary != "" {
+		return d.binary
+	}
+	return "pg_dump"
+}
+
+// options returns the pg_dump command line arguments
+func (d *Dump) options() ([]string, error) {
+	if err := d.ValidateOptions(); err != nil {
+		return nil, err
+	}
+
+	format := d.Format
+	if format == "" {
+		format = DumpFormatPlain
+	}
+
+	opts := []string{
+		"--format", format,
+		"--no-owner", // skip restoration of object ownership in plain-text format
+	}
+
+	// Clean (drop) database objects before recreating, not supported for data-only dumps
+	if !d.DataOnly {
+		opts = append(opts, "--clean")
+	}
+
+	// Compression level for compressed formats, tar archives can't be compressed
+	if format != DumpFormatTar {
+		opts = append(opts, "--compress", "6")
+	}
+
+	if d.SchemaOnly {
+		opts = append(opts, "--schema-only")
+	}
+	if d.DataOnly {
+		opts = append(opts, "--data-only")
+	}
+	if d.ColumnInserts {
+		opts = append(opts, "--column-inserts")
+	} else if d.Inserts {
+		opts = append(opts, "--inserts")
+	}
+	if d.NoPrivileges {
+		opts = append(opts, "--no-privileges")
+	}
+
+	if d.Table != "" {
+		opts = append(opts, []string{"--table", d.Table}...)
+	}
+	for _, pattern := range d.Tables {
+		opts = append(opts, "--table", pattern)
+	}
+	for _, pattern := range d.ExcludeTables {
+		opts = append(opts, "--exclude-table", pattern)
+	}
+	for _, pattern := range d.Schemas {
+		opts = append(opts, "--schema", pattern)
+	}
+	for _, pattern := range d.ExcludeSchemas {
+		opts = append(opts, "--exclude-schema", pattern)
+	}
+
+	return opts, nil
+}
+
 // removeUnsupportedOptions removes any options unsupported for making a db dump
 func removeUnsupportedOptions(input string) (string, error) {
 	uri, err := url.Parse(input)
@@ -53,3 +53,88 @@ func testDumpExport(t *testing.T) {
 	err = dump.Export(context.Background(), searchPathURL, saveFile)
 	assert.NoError(t, err)
 }
+
+func TestDumpOptions(t *testing.T) {
+	examples := []struct {
+		name    string
+		dump    Dump
+		options []string
+		err     string
+	}{
+		{
+			name:    "defaults",
+			dump:    Dump{},
+			options: []string{"--format", "plain", "--no-owner", "--clean", "--compress", "6"},
+		},
+		{
+			name:    "single table",
+			dump:    Dump{Table: "books"},
+			options: []string{"--format", "plain", "--no-owner", "--clean", "--compress", "6", "--table", "books"},
+		},
+		{
+			name: "custom format with patterns",
+			dump: Dump{
+				Format:         DumpFormatCustom,
+				SchemaOnly:     true,
+				NoPrivileges:   true,
+				Tables:         []string{"public.*"},
+				ExcludeTables:  []string{"public.logs"},
+				Schemas:        []string{"public"},
+				ExcludeSchemas: []string{"audit"},
+			},
+			options: []string{
+				"--format", "custom", "--no-owner", "--clean", "--compress", "6", "--schema-only", "--no-privileges",
+				"--table", "public.*", "--exclude-table", "public.logs", "--schema", "public", "--exclude-schema", "audit",
+			},
+		},
+		{
+			name:    "tar data-only with inserts",
+			dump:    Dump{Format: DumpFormatTar, DataOnly: true, Inserts: true, ColumnInserts: true},
+			options: []string{"--format", "tar", "--no-owner", "--data-only", "--column-inserts"},
+		},
+		{
+			name: "invalid format",
+			dump: Dump{Format: "directory"},
+			err:  "invalid dump format: directory",
+		},
+		{
+			name: "schema and data only",
+			dump: Dump{SchemaOnly: true, DataOnly: true},
+			err:  "schema-only and data-only options can't be used together",
+		},
+		{
+			name: "schema only with inserts",
+			dump: Dump{SchemaOnly: true, Inserts: true},
+			err:  "inserts options can't be used with schema-only dumps",
+		},
+		{
+			name: "option injection",
+			dump: Dump{Tables: []string{"--file=/tmp/foo"}},
+			err:  `invalid dump pattern: "--file=/tmp/foo"`,
+		},
+		{
+			name: "empty pattern",
+			dump: Dump{ExcludeSchemas: []string{" "}},
+			err:  "dump patterns can't be empty",
+		},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			options, err := ex.dump.options()
+			if ex.err != "" {
+				assert.EqualError(t, err, ex.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, ex.options, options)
+		})
+	}
+}
+
+func TestDumpExtension(t *testing.T) {
+	assert.Equal(t, "sql.gz", (&Dump{}).Extension())
+	assert.Equal(t, "sql.gz", (&Dump{Format: DumpFormatPlain}).Extension())
+	assert.Equal(t, "dump", (&Dump{Format: DumpFormatCustom}).Extension())
+	assert.Equal(t, "tar", (&Dump{Format: DumpFormatTar}).Extension())
+}
@@ -0,0 +1,353 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// Registered exporters, keyed by format name
+	exporters = map[string]Exporter{}
+
+	// Escapes special characters in tab-separated values
+	tsvEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		"\t", `\t`,
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+
+	// Escapes special characters in markdown table cells
+	markdownEscaper = strings.NewReplacer(
+		`|`, `\|`,
+		"\r\n", "<br>",
+		"\n", "<br>",
+		"\r", "<br>",
+	)
+)
+
+// Exporter writes a query result in a specific file format
+type Exporter interface {
+	// ContentType returns the MIME type of the output
+	ContentType() string
+	// Extension returns the file extension of the output
+	Extension() string
+	// Export writes the result to the writer
+	Export(w io.Writer, res *Result, opts ExportOptions) error
+}
+
+// ExportOptions contains parameters shared by the exporters
+type ExportOptions struct {
+	Table string     // Target table name for SQL statements
+	CSV   CSVOptions // Formatting of the CSV output
+}
+
+func init() {
+	RegisterExporter("csv", csvExporter{})
+	RegisterExporter("json", jsonExporter{})
+	RegisterExporter("xml", xmlExporter{})
+	RegisterExporter("ndjson", ndjsonExporter{})
+	RegisterExporter("tsv", tsvExporter{})
+	RegisterExporter("markdown", markdownExporter{})
+	RegisterExporter("html", htmlExporter{})
+	RegisterExporter("sql", sqlExporter{})
+	RegisterExporter("xlsx", xlsxExporter{})
+}
+
+// RegisterExporter makes the exporter available under the given format name
+func RegisterExporter(format string, exporter Exporter) {
+	exporters[format] = exporter
+}
+
+// GetExporter returns the exporter registered for the format
+func GetExporter(format string) (Exporter, error) {
+	exporter, ok := exporters[format]
+	if !ok {
+		return nil, fmt.Errorf("invalid export format: %v", format)
+	}
+	return exporter, nil
+}
+
+// ExportFormats returns a sorted list of registered export formats
+func ExportFormats() []string {
+	formats := make([]string, 0, len(exporters))
+	for name := range exporters {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// exportValue formats a single value for text-based exporters
+func exportValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+type csvExporter struct{}
+
+func (csvExporter) ContentType() string { return "text/csv" }
+func (csvExporter) Extension() string   { return "csv" }
+
+func (csvExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	return res.WriteCSV(w, opts.CSV)
+}
+
+type jsonExporter struct{}
+
+func (jsonExporter) ContentType() string { return "application/json" }
+func (jsonExporter) Extension() string   { return "json" }
+
+func (jsonExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	_, err := w.Write(res.JSON())
+	return err
+}
+
+type ndjsonExporter struct{}
+
+func (ndjsonExporter) ContentType() string { return "application/x-ndjson" }
+func (ndjsonExporter) Extension() string   { return "ndjson" }
+
+// Export writes every row as a JSON object, keeping keys in the column order
+func (ndjsonExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	keys := make([][]byte, len(res.Columns))
+	for i, col := range res.Columns {
+		keys[i], _ = json.Marshal(col)
+	}
+
+	for _, row := range res.Rows {
+		line := []byte{'{'}
+		for i, val := range row {
+			data, err := json.Marshal(val)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				line = append(line, ',')
+			}
+			line = append(line, keys[i]...)
+			line = append(line, ':')
+			line = append(line, data...)
+		}
+		line = append(line, '}', '\n')
+
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type tsvExporter struct{}
+
+func (tsvExporter) ContentType() string { return "text/tab-separated-values" }
+func (tsvExporter) Extension() string   { return "tsv" }
+
+// Export writes tab separated values, special characters are escaped with a backslash.
+// Header, NULL marker, BOM and time options are shared with CSV, the NULL marker is
+// written as is, so the \N marker could be told apart from the escaped text.
+func (tsvExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	csvOpts := opts.CSV
+	if err := csvOpts.Validate(); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	writeLine := func(values []string) error {
+		_, err := bw.WriteString(strings.Join(values, "\t") + "\n")
+		return err
+	}
+
+	if csvOpts.BOM {
+		bw.Write(utf8BOM) //nolint
+	}
+
+	if !csvOpts.SkipHeader {
+		values := make([]string, len(res.Columns))
+		for i, name := range res.Columns {
+			values[i] = tsvEscaper.Replace(name)
+		}
+		if err := writeLine(values); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range res.Rows {
+		values := make([]string, len(row))
+		for i, val := range row {
+			switch v := val.(type) {
+			case nil:
+				values[i] = csvOpts.Null
+			case time.Time:
+				values[i] = tsvEscaper.Replace(formatCSVTime(v, csvOpts))
+			default:
+				values[i] = tsvEscaper.Replace(exportValue(v))
+			}
+		}
+		if err := writeLine(values); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+type markdownExporter struct{}
+
+func (markdownExporter) ContentType() string { return "text/markdown" }
+func (markdownExporter) Extension() string   { return "md" }
+
+func (markdownExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	writeLine := func(values []string) error {
+		for i, val := range values {
+			values[i] = markdownEscaper.Replace(val)
+		}
+		_, err := io.WriteString(w, "| "+strings.Join(values, " | ")+" |\n")
+		return err
+	}
+
+	if err := writeLine(append([]string{}, res.Columns...)); err != nil {
+		return err
+	}
+
+	separator := make([]string, len(res.Columns))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	if _, err := io.WriteString(w, "| "+strings.Join(separator, " | ")+" |\n"); err != nil {
+		return err
+	}
+
+	for _, row := range res.Rows {
+		values := make([]string, len(row))
+		for i, val := range row {
+			values[i] = exportValue(val)
+		}
+		if err := writeLine(values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type htmlExporter struct{}
+
+func (htmlExporter) ContentType() string { return "text/html; charset=utf-8" }
+func (htmlExporter) Extension() string   { return "html" }
+
+func (htmlExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	buf := &strings.Builder{}
+
+	buf.WriteString("<table>\n<thead>\n<tr>")
+	for _, col := range res.Columns {
+		buf.WriteString("<th>" + html.EscapeString(col) + "</th>")
+	}
+	buf.WriteString("</tr>\n</thead>\n<tbody>\n")
+
+	for _, row := range res.Rows {
+		buf.WriteString("<tr>")
+		for _, val := range row {
+			buf.WriteString("<td>" + html.EscapeString(exportValue(val)) + "</td>")
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</tbody>\n</table>\n")
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+type xmlExporter struct{}
+
+func (xmlExporter) ContentType() string { return "application/xml" }
+func (xmlExporter) Extension() string   { return "xml" }
+
+// Export writes rows as <row> elements with a <column> element per value.
+// NULL values are marked with the null attribute.
+func (xmlExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	if _, err := io.WriteString(w, xml.Header+"<results>\n"); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("  ", "  ")
+
+	for _, row := range res.Rows {
+		rowStart := xml.StartElement{Name: xml.Name{Local: "row"}}
+		if err := encoder.EncodeToken(rowStart); err != nil {
+			return err
+		}
+
+		for i, val := range row {
+			colStart := xml.StartElement{
+				Name: xml.Name{Local: "column"},
+				Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: res.Columns[i]}},
+			}
+			if val == nil {
+				colStart.Attr = append(colStart.Attr, xml.Attr{Name: xml.Name{Local: "null"}, Value: "true"})
+			}
+			if err := encoder.EncodeElement(exportValue(val), colStart); err != nil {
+				return err
+			}
+		}
+
+		if err := encoder.EncodeToken(rowStart.End()); err != nil {
+			return err
+		}
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n</results>\n")
+	return err
+}
+
+type sqlExporter struct{}
+
+func (sqlExporter) ContentType() string { return "application/sql" }
+func (sqlExporter) Extension() string   { return "sql" }
+
+// Export writes an INSERT statement for every row into the target table
+func (sqlExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	if strings.TrimSpace(opts.Table) == "" {
+		return errors.New("table name is required for sql export")
+	}
+
+	columns := make([]string, len(res.Columns))
+	for i, col := range res.Columns {
+		columns[i] = pq.QuoteIdentifier(col)
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES (", quoteTable(opts.Table), strings.Join(columns, ", "))
+
+	for _, row := range res.Rows {
+		values := make([]string, len(row))
+		for i, val := range row {
+			values[i] = sqlLiteral(val, "")
+		}
+		if _, err := io.WriteString(w, prefix+strings.Join(values, ", ")+");\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportResult(t *testing.T, format string, res *Result, opts ExportOptions) string {
+	t.Helper()
+
+	exporter, err := GetExporter(format)
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, exporter.Export(buf, res, opts))
+
+	return buf.String()
+}
+
+func TestExporters(t *testing.T) {
+	res := &Result{
+		Columns: []string{"id", "name", "note"},
+		Rows: []Row{
+			{int64(1), "John", "a|b\tc"},
+			{int64(2), "O'Neil <Bob>", nil},
+		},
+	}
+
+	t.Run("formats", func(t *testing.T) {
+		assert.Equal(t, []string{"csv", "html", "json", "markdown", "ndjson", "sql", "tsv", "xlsx", "xml"}, ExportFormats())
+
+		_, err := GetExporter("yaml")
+		assert.EqualError(t, err, "invalid export format: yaml")
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		expected := `{"id":1,"name":"John","note":"a|b\tc"}` + "\n" +
+			`{"id":2,"name":"O'Neil \u003cBob\u003e","note":null}` + "\n"
+		assert.Equal(t, expected, exportResult(t, "ndjson", res, ExportOptions{}))
+	})
+
+	t.Run("tsv", func(t *testing.T) {
+		expected := "id\tname\tnote\n1\tJohn\ta|b\\tc\n2\tO'Neil <Bob>\t\n"
+		assert.Equal(t, expected, exportResult(t, "tsv", res, ExportOptions{}))
+	})
+
+	t.Run("tsv options", func(t *testing.T) {
+		res := &Result{
+			Columns: []string{"id", "note", "created_at"},
+			Rows: []Row{
+				{int64(1), `\N`, time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)},
+				{int64(2), nil, nil},
+			},
+		}
+		opts := ExportOptions{CSV: CSVOptions{SkipHeader: true, Null: `\N`, BOM: true, TimeFormat: "date"}}
+
+		expected := "\xEF\xBB\xBF" + "1\t\\\\N\t2024-03-01\n2\t\\N\t\\N\n"
+		assert.Equal(t, expected, exportResult(t, "tsv", res, opts))
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		expected := "| id | name | note |\n| --- | --- | --- |\n| 1 | John | a\\|b\tc |\n| 2 | O'Neil <Bob> |  |\n"
+		assert.Equal(t, expected, exportResult(t, "markdown", res, ExportOptions{}))
+	})
+
+	t.Run("html", func(t *testing.T) {
+		out := exportResult(t, "html", res, ExportOptions{})
+		assert.Contains(t, out, "<tr><th>id</th><th>name</th><th>note</th></tr>")
+		assert.Contains(t, out, "<tr><td>2</td><td>O&#39;Neil &lt;Bob&gt;</td><td></td></tr>")
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		out := exportResult(t, "xml", res, ExportOptions{})
+		assert.Contains(t, out, `<column name="name">O&#39;Neil &lt;Bob&gt;</column>`)
+		assert.Contains(t, out, `<column name="note" null="true"></column>`)
+	})
+
+	t.Run("sql", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		assert.EqualError(t, sqlExporter{}.Export(buf, res, ExportOptions{}), "table name is required for sql export")
+
+		expected := `INSERT INTO "public"."users" ("id", "name", "note") VALUES (1, 'John', 'a|b	c');` + "\n" +
+			`INSERT INTO "public"."users" ("id", "name", "note") VALUES (2, 'O''Neil <Bob>', NULL);` + "\n"
+		assert.Equal(t, expected, exportResult(t, "sql", res, ExportOptions{Table: "users"}))
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		out := exportResult(t, "xlsx", res, ExportOptions{})
+
+		archive, err := zip.NewReader(bytes.NewReader([]byte(out)), int64(len(out)))
+		require.NoError(t, err)
+
+		names := []string{}
+		var sheet string
+		for _, file := range archive.File {
+			names = append(names, file.Name)
+			if file.Name == "xl/worksheets/sheet1.xml" {
+				r, err := file.Open()
+				require.NoError(t, err)
+				data, err := io.ReadAll(r)
+				require.NoError(t, err)
+				sheet = string(data)
+			}
+		}
+
+		assert.Contains(t, names, "[Content_Types].xml")
+		assert.Contains(t, sheet, `<c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+		assert.Contains(t, sheet, `<c r="A3"><v>2</v></c>`)
+		assert.Contains(t, sheet, `<t xml:space="preserve">O&#39;Neil &lt;Bob&gt;</t>`)
+		assert.NotContains(t, sheet, `r="C3"`)
+	})
+}
+
+func TestXlsxColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "AZ", xlsxColumnName(51))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"strconv"
+)
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Results" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+type xlsxExporter struct{}
+
+func (xlsxExporter) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (xlsxExporter) Extension() string { return "xlsx" }
+
+// Export writes a single-sheet workbook with the column names in the first row.
+// Strings are stored inline so the workbook does not need a shared strings table.
+func (xlsxExporter) Export(w io.Writer, res *Result, opts ExportOptions) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+
+	for _, file := range files {
+		fw, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, file.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if err := writeXlsxSheet(sheet, res); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func writeXlsxSheet(w io.Writer, res *Result) error {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xlsxSheetHeader)
+
+	header := make([]interface{}, len(res.Columns))
+	for i, col := range res.Columns {
+		header[i] = col
+	}
+	writeXlsxRow(buf, 1, header)
+
+	for i, row := range res.Rows {
+		writeXlsxRow(buf, i+2, row)
+
+		// Keep memory usage in check on large results
+		if buf.Len() > 64*1024 {
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+	}
+
+	buf.WriteString(xlsxSheetFooter)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeXlsxRow(buf *bytes.Buffer, num int, values []interface{}) {
+	rowNum := strconv.Itoa(num)
+	buf.WriteString(`<row r="` + rowNum + `">`)
+
+	for i, val := range values {
+		if val == nil {
+			continue
+		}
+
+		ref := xlsxColumnName(i) + rowNum
+
+		switch v := val.(type) {
+		case bool:
+			cell := "0"
+			if v {
+				cell = "1"
+			}
+			buf.WriteString(`<c r="` + ref + `" t="b"><v>` + cell + `</v></c>`)
+		case int64:
+			buf.WriteString(`<c r="` + ref + `"><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+		case int:
+			buf.WriteString(`<c r="` + ref + `"><v>` + strconv.Itoa(v) + `</v></c>`)
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				writeXlsxString(buf, ref, formatFloat(v))
+			} else {
+				buf.WriteString(`<c r="` + ref + `"><v>` + formatFloat(v) + `</v></c>`)
+			}
+		default:
+			writeXlsxString(buf, ref, exportValue(val))
+		}
+	}
+
+	buf.WriteString(`</row>`)
+}
+
+func writeXlsxString(buf *bytes.Buffer, ref string, str string) {
+	buf.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+	xml.EscapeText(buf, []byte(str)) //nolint
+	buf.WriteString(`</t></is></c>`)
+}
+
+// xlsxColumnName returns the spreadsheet column name for the zero-based index.
+// Example: 0 -> A, 25 -> Z, 26 -> AA
+func xlsxColumnName(idx int) string {
+	name := ""
+	for idx >= 0 {
+		name = string(rune('A'+idx%26)) + name
+		idx = idx/26 - 1
+	}
+	return name
+}
@@ -0,0 +1,519 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	// Number of rows validated in dry-run mode when not specified
+	defaultDryRunRows = 100
+
+	// Number of rows between progress callbacks
+	importProgressInterval = 10000
+)
+
+var (
+	errImportReadOnly = errors.New("import is not allowed in read-only mode")
+)
+
+// Import represents a table data import from a CSV or NDJSON stream
+type Import struct {
+	Table      string            // Target table, optionally schema-qualified
+	Format     string            // Input format: csv or ndjson
+	Delimiter  rune              // CSV field delimiter
+	Quote      rune              // CSV quote character
+	Header     bool              // CSV input starts with a header line
+	Null       string            // CSV marker for NULL values, only matched when unquoted
+	Columns    []string          // Target columns for the input fields, in input order
+	Mapping    map[string]string // Input field name to target column
+	DryRun     bool              // Validate the first rows and roll back
+	DryRunRows int               // Number of rows to validate in dry-run mode
+}
+
+// ImportResult contains the outcome of the data import
+type ImportResult struct {
+	Table     string   `json:"table"`
+	Columns   []string `json:"columns"`
+	RowsCount int64    `json:"rows_count"`
+	DryRun    bool     `json:"dry_run"`
+	Duration  int64    `json:"duration_ms"`
+}
+
+// importSource produces rows for the COPY statement
+type importSource interface {
+	// Columns returns the list of target columns
+	Columns() []string
+	// Next returns values for the next row, or io.EOF when input is exhausted
+	Next() ([]interface{}, error)
+}
+
+// Validate checks the import options and fills in defaults
+func (imp *Import) Validate() error {
+	if strings.TrimSpace(imp.Table) == "" {
+		return errors.New("table name is required")
+	}
+
+	switch imp.Format {
+	case "":
+		imp.Format = ImportFormatCSV
+	case ImportFormatCSV, ImportFormatNDJSON:
+	default:
+		return fmt.Errorf("invalid import format: %v", imp.Format)
+	}
+
+	if imp.Delimiter == 0 {
+		imp.Delimiter = ','
+	}
+	if imp.Quote == 0 {
+		imp.Quote = '"'
+	}
+	if imp.Delimiter == imp.Quote {
+		return errors.New("delimiter and quote characters must be different")
+	}
+	if imp.Delimiter == '\n' || imp.Delimiter == '\r' || imp.Quote == '\n' || imp.Quote == '\r' {
+		return errors.New("delimiter and quote characters can't be line breaks")
+	}
+
+	if len(imp.Mapping) > 0 && len(imp.Columns) > 0 {
+		return errors.New("columns and mapping options can't be used together")
+	}
+	if len(imp.Mapping) > 0 && imp.Format == ImportFormatCSV && !imp.Header {
+		return errors.New("column mapping requires a CSV header")
+	}
+
+	if imp.DryRun && imp.DryRunRows <= 0 {
+		imp.DryRunRows = defaultDryRunRows
+	}
+
+	return nil
+}
+
+// Import streams the input into the target table using COPY FROM STDIN.
+// All rows are loaded in a single transaction, so any error leaves the table intact.
+func (client *Client) Import(ctx context.Context, imp *Import, input io.Reader, progress func(rows int64)) (*ImportResult, error) {
+	if client.IsReadOnly() {
+		return nil, errImportReadOnly
+	}
+	if err := imp.Validate(); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		client.lastQueryTime = time.Now().UTC()
+	}()
+
+	schema, table := getSchemaAndTable(imp.Table)
+
+	var (
+		source importSource
+		err    error
+	)
+
+	switch imp.Format {
+	case ImportFormatNDJSON:
+		source, err = newNDJSONSource(input, imp)
+	default:
+		source, err = newCSVSource(input, imp, func() ([]string, error) {
+			return client.fetchRows(statements.TableColumns, schema, table)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		Table:   fmt.Sprintf("%s.%s", schema, table),
+		Columns: source.Columns(),
+		DryRun:  imp.DryRun,
+	}
+	start := time.Now()
+
+	tx, err := client.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyInSchema(schema, table, result.Columns...))
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	for {
+		if imp.DryRun && result.RowsCount >= int64(imp.DryRunRows) {
+			break
+		}
+
+		values, err := source.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return nil, err
+		}
+		result.RowsCount++
+
+		if progress != nil && result.RowsCount%importProgressInterval == 0 {
+			progress(result.RowsCount)
+		}
+	}
+
+	// Flush the remaining buffered data and complete the COPY
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return nil, err
+	}
+	if err := stmt.Close(); err != nil {
+		return nil, err
+	}
+
+	if !imp.DryRun {
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Duration = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// csvSource reads rows from a delimited text input
+type csvSource struct {
+	reader  *csvReader
+	columns []string
+	indexes []int
+	null    string
+	fields  int
+}
+
+func newCSVSource(input io.Reader, imp *Import, tableColumns func() ([]string, error)) (*csvSource, error) {
+	src := &csvSource{
+		reader: newCSVReader(input, imp.Delimiter, imp.Quote),
+		null:   imp.Null,
+	}
+
+	var header []string
+	if imp.Header {
+		fields, _, err := src.reader.Read()
+		if err == io.EOF {
+			return nil, errors.New("input is empty")
+		}
+		if err != nil {
+			return nil, err
+		}
+		header = fields
+		src.fields = len(header)
+	}
+
+	switch {
+	case len(imp.Mapping) > 0:
+		found := map[string]bool{}
+		for idx, name := range header {
+			if target, ok := imp.Mapping[name]; ok {
+				src.indexes = append(src.indexes, idx)
+				src.columns = append(src.columns, target)
+				found[name] = true
+			}
+		}
+		for name := range imp.Mapping {
+			if !found[name] {
+				return nil, fmt.Errorf("mapped field %q is not present in the header", name)
+			}
+		}
+	case len(imp.Columns) > 0:
+		if header != nil && len(imp.Columns) != len(header) {
+			return nil, fmt.Errorf("expected %d columns to match the header, got %d", len(header), len(imp.Columns))
+		}
+		// Empty column names skip the corresponding input fields
+		for idx, name := range imp.Columns {
+			if name == "" {
+				continue
+			}
+			src.indexes = append(src.indexes, idx)
+			src.columns = append(src.columns, name)
+		}
+		src.fields = len(imp.Columns)
+	case header != nil:
+		for idx, name := range header {
+			src.indexes = append(src.indexes, idx)
+			src.columns = append(src.columns, name)
+		}
+	default:
+		columns, err := tableColumns()
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("table %q does not exist or has no columns", imp.Table)
+		}
+		for idx, name := range columns {
+			src.indexes = append(src.indexes, idx)
+			src.columns = append(src.columns, name)
+		}
+		src.fields = len(columns)
+	}
+
+	if len(src.columns) == 0 {
+		return nil, errors.New("no columns selected for import")
+	}
+
+	return src, nil
+}
+
+func (src *csvSource) Columns() []string {
+	return src.columns
+}
+
+func (src *csvSource) Next() ([]interface{}, error) {
+	fields, quoted, err := src.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) != src.fields {
+		return nil, fmt.Errorf("line %d: expected %d fields, got %d", src.reader.line, src.fields, len(fields))
+	}
+
+	values := make([]interface{}, len(src.indexes))
+	for i, idx := range src.indexes {
+		if !quoted[idx] && fields[idx] == src.null {
+			values[i] = nil
+		} else {
+			values[i] = fields[idx]
+		}
+	}
+
+	return values, nil
+}
+
+// ndjsonSource reads rows from a newline-delimited JSON input, one object per line
+type ndjsonSource struct {
+	scanner *bufio.Scanner
+	keys    []string
+	columns []string
+	first   map[string]interface{}
+	line    int
+}
+
+func newNDJSONSource(input io.Reader, imp *Import) (*ndjsonSource, error) {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	src := &ndjsonSource{scanner: scanner}
+
+	switch {
+	case len(imp.Mapping) > 0:
+		for key := range imp.Mapping {
+			src.keys = append(src.keys, key)
+		}
+		sort.Strings(src.keys)
+		for _, key := range src.keys {
+			src.columns = append(src.columns, imp.Mapping[key])
+		}
+	case len(imp.Columns) > 0:
+		src.keys = imp.Columns
+		src.columns = imp.Columns
+	default:
+		// Use keys of the first object when columns are not specified
+		first, err := src.readObject()
+		if err == io.EOF {
+			return nil, errors.New("input is empty")
+		}
+		if err != nil {
+			return nil, err
+		}
+		for key := range first {
+			src.keys = append(src.keys, key)
+		}
+		sort.Strings(src.keys)
+		src.columns = src.keys
+		src.first = first
+	}
+
+	return src, nil
+}
+
+func (src *ndjsonSource) Columns() []string {
+	return src.columns
+}
+
+func (src *ndjsonSource) Next() ([]interface{}, error) {
+	obj := src.first
+	src.first = nil
+
+	if obj == nil {
+		var err error
+		if obj, err = src.readObject(); err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([]interface{}, len(src.keys))
+	for i, key := range src.keys {
+		switch v := obj[key].(type) {
+		case nil:
+			values[i] = nil
+		case json.Number:
+			values[i] = v.String()
+		case string, bool:
+			values[i] = v
+		default:
+			// Nested objects and arrays are loaded as JSON text
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = string(data)
+		}
+	}
+
+	return values, nil
+}
+
+func (src *ndjsonSource) readObject() (map[string]interface{}, error) {
+	for src.scanner.Scan() {
+		src.line++
+
+		line := strings.TrimSpace(src.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		decoder := json.NewDecoder(strings.NewReader(line))
+		decoder.UseNumber()
+
+		obj := map[string]interface{}{}
+		if err := decoder.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("line %d: %v", src.line, err)
+		}
+		return obj, nil
+	}
+
+	if err := src.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// csvReader parses delimited text with configurable delimiter and quote characters.
+// Unlike encoding/csv it reports which fields were quoted, so that quoted values
+// are never treated as NULL.
+type csvReader struct {
+	r         *bufio.Reader
+	delimiter rune
+	quote     rune
+	line      int
+}
+
+func newCSVReader(input io.Reader, delimiter, quote rune) *csvReader {
+	return &csvReader{
+		r:         bufio.NewReader(input),
+		delimiter: delimiter,
+		quote:     quote,
+	}
+}
+
+// Read returns fields of the next record and flags for quoted fields
+func (r *csvReader) Read() ([]string, []bool, error) {
+	var (
+		fields   []string
+		quoted   []bool
+		field    strings.Builder
+		inQuotes bool
+		isQuoted bool
+		started  bool
+	)
+
+	r.line++
+	startLine := r.line
+
+	finish := func() ([]string, []bool, error) {
+		return append(fields, field.String()), append(quoted, isQuoted), nil
+	}
+
+	for {
+		ch, _, err := r.r.ReadRune()
+		if err == io.EOF {
+			if inQuotes {
+				return nil, nil, fmt.Errorf("line %d: unterminated quoted field", startLine)
+			}
+			if !started {
+				return nil, nil, io.EOF
+			}
+			return finish()
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if inQuotes {
+			if ch == r.quote {
+				next, _, err := r.r.ReadRune()
+				if err == nil && next == r.quote {
+					field.WriteRune(ch)
+					continue
+				}
+				if err == nil {
+					r.r.UnreadRune() //nolint
+				}
+				inQuotes = false
+				continue
+			}
+			if ch == '\n' {
+				r.line++
+			}
+			field.WriteRune(ch)
+			continue
+		}
+
+		switch ch {
+		case '\r', '\n':
+			if ch == '\r' {
+				if next, _, err := r.r.ReadRune(); err == nil && next != '\n' {
+					r.r.UnreadRune() //nolint
+				}
+			}
+			// Skip blank lines
+			if !started {
+				r.line++
+				startLine = r.line
+				continue
+			}
+			return finish()
+		case r.delimiter:
+			fields = append(fields, field.String())
+			quoted = append(quoted, isQuoted)
+			field.Reset()
+			isQuoted = false
+		case r.quote:
+			if field.Len() == 0 && !isQuoted {
+				inQuotes = true
+				isQuoted = true
+			} else {
+				field.WriteRune(ch)
+			}
+		default:
+			field.WriteRune(ch)
+		}
+		started = true
+	}
+}
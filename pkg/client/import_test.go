@@ -0,0 +1,191 @@
+package client
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportValidate(t *testing.T) {
+	examples := []struct {
+		name  string
+		input Import
+		err   string
+	}{
+		{"missing table", Import{}, "table name is required"},
+		{"invalid format", Import{Table: "foo", Format: "xml"}, "invalid import format: xml"},
+		{"same delimiter and quote", Import{Table: "foo", Delimiter: '"'}, "delimiter and quote characters must be different"},
+		{"columns and mapping", Import{Table: "foo", Columns: []string{"a"}, Mapping: map[string]string{"a": "b"}}, "columns and mapping options can't be used together"},
+		{"mapping without header", Import{Table: "foo", Mapping: map[string]string{"a": "b"}}, "column mapping requires a CSV header"},
+		{"valid", Import{Table: "foo"}, ""},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			err := ex.input.Validate()
+			if ex.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, ex.err)
+			}
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		imp := Import{Table: "foo", DryRun: true}
+		assert.NoError(t, imp.Validate())
+		assert.Equal(t, ImportFormatCSV, imp.Format)
+		assert.Equal(t, ',', imp.Delimiter)
+		assert.Equal(t, '"', imp.Quote)
+		assert.Equal(t, defaultDryRunRows, imp.DryRunRows)
+	})
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "a,b,c\r\n1,\"two, \"\"2\"\"\",\n\n'x';\"y\n"
+	reader := newCSVReader(strings.NewReader(input), ',', '"')
+
+	fields, quoted, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, fields)
+	assert.Equal(t, []bool{false, false, false}, quoted)
+
+	fields, quoted, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", `two, "2"`, ""}, fields)
+	assert.Equal(t, []bool{false, true, false}, quoted)
+
+	fields, _, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`'x';"y`}, fields)
+
+	_, _, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+
+	t.Run("custom delimiter and quote", func(t *testing.T) {
+		reader := newCSVReader(strings.NewReader("'a;b';'multi\nline'\n"), ';', '\'')
+
+		fields, quoted, err := reader.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a;b", "multi\nline"}, fields)
+		assert.Equal(t, []bool{true, true}, quoted)
+		assert.Equal(t, 2, reader.line)
+	})
+
+	t.Run("unterminated quote", func(t *testing.T) {
+		reader := newCSVReader(strings.NewReader("\"abc\n"), ',', '"')
+
+		_, _, err := reader.Read()
+		assert.EqualError(t, err, "line 1: unterminated quoted field")
+	})
+}
+
+func TestCSVSource(t *testing.T) {
+	noColumns := func() ([]string, error) { return nil, nil }
+
+	t.Run("header and null marker", func(t *testing.T) {
+		imp := &Import{Table: "foo", Header: true, Null: "NULL"}
+		require.NoError(t, imp.Validate())
+
+		src, err := newCSVSource(strings.NewReader("id,name\n1,NULL\n2,\"NULL\"\n"), imp, noColumns)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, src.Columns())
+
+		values, err := src.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"1", nil}, values)
+
+		values, err = src.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"2", "NULL"}, values)
+
+		_, err = src.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("mapping", func(t *testing.T) {
+		imp := &Import{Table: "foo", Header: true, Mapping: map[string]string{"Name": "name"}}
+		require.NoError(t, imp.Validate())
+
+		src, err := newCSVSource(strings.NewReader("ID,Name\n1,Bob\n"), imp, noColumns)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"name"}, src.Columns())
+
+		values, err := src.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"Bob"}, values)
+
+		imp.Mapping = map[string]string{"Email": "email"}
+		_, err = newCSVSource(strings.NewReader("ID,Name\n1,Bob\n"), imp, noColumns)
+		assert.EqualError(t, err, `mapped field "Email" is not present in the header`)
+	})
+
+	t.Run("columns with skipped fields", func(t *testing.T) {
+		imp := &Import{Table: "foo", Columns: []string{"id", "", "name"}}
+		require.NoError(t, imp.Validate())
+
+		src, err := newCSVSource(strings.NewReader("1,x,Bob\n2,y\n"), imp, noColumns)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, src.Columns())
+
+		values, err := src.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"1", "Bob"}, values)
+
+		_, err = src.Next()
+		assert.EqualError(t, err, "line 2: expected 3 fields, got 2")
+	})
+
+	t.Run("table columns", func(t *testing.T) {
+		imp := &Import{Table: "foo"}
+		require.NoError(t, imp.Validate())
+
+		src, err := newCSVSource(strings.NewReader("1,Bob\n"), imp, func() ([]string, error) {
+			return []string{"id", "name"}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, src.Columns())
+
+		_, err = newCSVSource(strings.NewReader("1,Bob\n"), imp, noColumns)
+		assert.EqualError(t, err, `table "foo" does not exist or has no columns`)
+	})
+}
+
+func TestNDJSONSource(t *testing.T) {
+	input := `{"name":"Bob","id":1,"tags":["a"],"active":true}` + "\n\n" + `{"id":2.5,"name":null}` + "\n"
+
+	t.Run("keys of first object", func(t *testing.T) {
+		src, err := newNDJSONSource(strings.NewReader(input), &Import{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"active", "id", "name", "tags"}, src.Columns())
+
+		values, err := src.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{true, "1", "Bob", `["a"]`}, values)
+
+		values, err = src.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{nil, "2.5", nil, nil}, values)
+
+		_, err = src.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("mapping", func(t *testing.T) {
+		src, err := newNDJSONSource(strings.NewReader(input), &Import{Mapping: map[string]string{"name": "full_name"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"full_name"}, src.Columns())
+
+		values, err := src.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"Bob"}, values)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := newNDJSONSource(strings.NewReader("\n{foo}\n"), &Import{})
+		assert.EqualError(t, err, "line 2: invalid character 'f' looking for beginning of object key string")
+	})
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	MaintenanceVacuum  = "vacuum"
+	MaintenanceAnalyze = "analyze"
+	MaintenanceReindex = "reindex"
+)
+
+// MaintenanceResult contains the outcome of a maintenance command
+type MaintenanceResult struct {
+	Table    string `json:"table"`
+	Action   string `json:"action"`
+	Duration int64  `json:"duration_ms"`
+}
+
+// Maintenance returns vacuum and analyze statistics of user tables along with
+// the transaction ID age and wraparound risk
+func (client *Client) Maintenance() (*Result, error) {
+	if client.serverType == cockroachType {
+		return nil, errors.New("maintenance view is not supported on CockroachDB")
+	}
+	return client.query(statements.Maintenance)
+}
+
+// MaintenanceCommand returns the statement for the maintenance action on the table
+func (client *Client) MaintenanceCommand(table string, action string) (string, error) {
+	if client.serverType == cockroachType {
+		return "", errors.New("maintenance actions are not supported on CockroachDB")
+	}
+
+	switch action {
+	case MaintenanceVacuum:
+		return "VACUUM (ANALYZE) " + quoteTable(table), nil
+	case MaintenanceAnalyze:
+		return "ANALYZE " + quoteTable(table), nil
+	case MaintenanceReindex:
+		// Concurrent reindex is available since 12
+		major, _ := getMajorMinorVersion(client.serverVersion)
+		if major < 12 {
+			return "", fmt.Errorf("REINDEX CONCURRENTLY is not supported on PostgreSQL %v", client.serverVersion)
+		}
+		return "REINDEX TABLE CONCURRENTLY " + quoteTable(table), nil
+	default:
+		return "", fmt.Errorf("invalid maintenance action: %v", action)
+	}
+}
+
+// RunMaintenance runs the maintenance action on the table. Statements are executed
+// outside of a transaction block and are not limited by the query timeout.
+func (client *Client) RunMaintenance(ctx context.Context, table string, action string) (*MaintenanceResult, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.IsReadOnly() {
+		return nil, errors.New("maintenance is not allowed in read-only mode")
+	}
+
+	query, err := client.MaintenanceCommand(table, action)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		client.lastQueryTime = time.Now().UTC()
+	}()
+
+	start := time.Now()
+	if _, err := client.db.ExecContext(ctx, query); err != nil {
+		return nil, err
+	}
+
+	schema, name := getSchemaAndTable(table)
+
+	return &MaintenanceResult{
+		Table:    schema + "." + name,
+		Action:   action,
+		Duration: time.Since(start).Milliseconds(),
+	}, nil
+}
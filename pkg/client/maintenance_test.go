@@ -0,0 +1,30 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceCommand(t *testing.T) {
+	client := &Client{serverType: postgresType, serverVersion: "11.5"}
+
+	query, err := client.MaintenanceCommand("books", MaintenanceVacuum)
+	assert.NoError(t, err)
+	assert.Equal(t, `VACUUM (ANALYZE) "public"."books"`, query)
+
+	query, err = client.MaintenanceCommand("sales.Orders", MaintenanceAnalyze)
+	assert.NoError(t, err)
+	assert.Equal(t, `ANALYZE "sales"."Orders"`, query)
+
+	_, err = client.MaintenanceCommand("books", MaintenanceReindex)
+	assert.EqualError(t, err, "REINDEX CONCURRENTLY is not supported on PostgreSQL 11.5")
+
+	_, err = client.MaintenanceCommand("books", "cluster")
+	assert.EqualError(t, err, "invalid maintenance action: cluster")
+
+	client.serverVersion = "12.1"
+	query, err = client.MaintenanceCommand("books", MaintenanceReindex)
+	assert.NoError(t, err)
+	assert.Equal(t, `REINDEX TABLE CONCURRENTLY "public"."books"`, query)
+}
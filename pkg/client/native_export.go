@@ -0,0 +1,305 @@
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	NativeFormatInsert = "insert"
+	NativeFormatCopy   = "copy"
+
+	// Number of rows in a single INSERT statement when not specified
+	defaultNativeBatchSize = 100
+)
+
+var (
+	// Special characters escaped in COPY text format
+	copyEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"\b", `\b`,
+		"\f", `\f`,
+		"\v", `\v`,
+	)
+)
+
+// NativeDump represents a table or query data export that does not require pg_dump.
+// Data is written as INSERT statements or COPY blocks and gzip-compressed.
+type NativeDump struct {
+	Table     string // Source table, optionally schema-qualified
+	Query     string // Source query, used instead of the table
+	Target    string // Table name used in the statements, defaults to the source table
+	Format    string // Output format: insert or copy
+	BatchSize int    // Number of rows per INSERT statement
+}
+
+// Validate checks the export options and fills in defaults
+func (d *NativeDump) Validate() error {
+	if d.Table == "" && d.Query == "" {
+		return errors.New("table or query is required")
+	}
+	if d.Table != "" && d.Query != "" {
+		return errors.New("table and query options can't be used together")
+	}
+	if d.Query != "" && d.Target == "" {
+		return errors.New("target table name is required for query exports")
+	}
+
+	switch d.Format {
+	case "":
+		d.Format = NativeFormatInsert
+	case NativeFormatInsert, NativeFormatCopy:
+	default:
+		return fmt.Errorf("invalid export format: %v", d.Format)
+	}
+
+	if d.BatchSize < 0 {
+		return errors.New("batch size must be greater than 0")
+	}
+	if d.BatchSize == 0 {
+		d.BatchSize = defaultNativeBatchSize
+	}
+
+	return nil
+}
+
+// Extension returns the file extension of the export output
+func (d *NativeDump) Extension() string {
+	return "sql.gz"
+}
+
+// NativeExport streams table or query data to the writer
+func (client *Client) NativeExport(ctx context.Context, d *NativeDump, writer io.Writer) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	query := d.Query
+	target := d.Target
+	if d.Table != "" {
+		query = "SELECT * FROM " + quoteTable(d.Table)
+		if target == "" {
+			target = d.Table
+		}
+	}
+
+	zw := gzip.NewWriter(writer)
+	out := &nativeWriter{
+		w:         zw,
+		table:     quoteTable(target),
+		format:    d.Format,
+		batchSize: d.BatchSize,
+	}
+
+	if err := out.header(); err != nil {
+		return err
+	}
+
+	err := client.streamRows(ctx, query, out.writeRow)
+	if err == nil {
+		err = out.finish()
+	}
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// nativeWriter formats rows as INSERT statements or COPY blocks
+type nativeWriter struct {
+	w         io.Writer
+	table     string
+	format    string
+	batchSize int
+	columns   []*sql.ColumnType
+	batch     []string
+	started   bool
+	err       error
+}
+
+func (nw *nativeWriter) printf(format string, args ...interface{}) {
+	if nw.err == nil {
+		_, nw.err = fmt.Fprintf(nw.w, format, args...)
+	}
+}
+
+func (nw *nativeWriter) header() error {
+	nw.printf("-- Data export generated by pgweb\n")
+	nw.printf("SET client_encoding = 'UTF8';\n")
+	nw.printf("SET standard_conforming_strings = on;\n\n")
+	return nw.err
+}
+
+func (nw *nativeWriter) columnList() string {
+	names := make([]string, len(nw.columns))
+	for i, col := range nw.columns {
+		names[i] = pq.QuoteIdentifier(col.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+func (nw *nativeWriter) writeRow(columns []*sql.ColumnType, row []interface{}) error {
+	if !nw.started {
+		nw.columns = columns
+		nw.started = true
+
+		if nw.format == NativeFormatCopy {
+			nw.printf("COPY %s (%s) FROM stdin;\n", nw.table, nw.columnList())
+		}
+	}
+
+	values := make([]string, len(row))
+	for i, val := range row {
+		typeName := columns[i].DatabaseTypeName()
+		if nw.format == NativeFormatCopy {
+			values[i] = copyValue(val, typeName)
+		} else {
+			values[i] = sqlLiteral(val, typeName)
+		}
+	}
+
+	if nw.format == NativeFormatCopy {
+		nw.printf("%s\n", strings.Join(values, "\t"))
+		return nw.err
+	}
+
+	nw.batch = append(nw.batch, "("+strings.Join(values, ", ")+")")
+	if len(nw.batch) >= nw.batchSize {
+		nw.flush()
+	}
+	return nw.err
+}
+
+func (nw *nativeWriter) flush() {
+	if len(nw.batch) == 0 {
+		return
+	}
+	nw.printf("INSERT INTO %s (%s) VALUES\n  %s;\n\n", nw.table, nw.columnList(), strings.Join(nw.batch, ",\n  "))
+	nw.batch = nw.batch[:0]
+}
+
+func (nw *nativeWriter) finish() error {
+	if nw.format == NativeFormatCopy {
+		if nw.started {
+			nw.printf("\\.\n\n")
+		}
+		return nw.err
+	}
+
+	nw.flush()
+	return nw.err
+}
+
+// quoteString returns a string literal, assuming standard_conforming_strings is on
+func quoteString(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", "''") + "'"
+}
+
+// formatTime formats the time value according to the column type
+func formatTime(val time.Time, typeName string) string {
+	switch typeName {
+	case "DATE":
+		return val.Format("2006-01-02")
+	case "TIME":
+		return val.Format("15:04:05.999999")
+	case "TIMETZ":
+		return val.Format("15:04:05.999999Z07:00")
+	case "TIMESTAMP":
+		return val.Format("2006-01-02 15:04:05.999999")
+	default:
+		return val.Format("2006-01-02 15:04:05.999999Z07:00")
+	}
+}
+
+// formatFloat formats the float value, special values are spelled out as Postgres expects
+func formatFloat(val float64) string {
+	switch {
+	case math.IsNaN(val):
+		return "NaN"
+	case math.IsInf(val, 1):
+		return "Infinity"
+	case math.IsInf(val, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+// sqlLiteral returns the value as a SQL literal for the given column type
+func sqlLiteral(val interface{}, typeName string) string {
+	switch v := val.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return quoteString(formatFloat(v))
+		}
+		return formatFloat(v)
+	case time.Time:
+		return quoteString(formatTime(v, typeName))
+	case []byte:
+		if typeName == "BYTEA" {
+			return "'\\x" + hex.EncodeToString(v) + "'"
+		}
+		return quoteString(string(v))
+	case string:
+		return quoteString(v)
+	default:
+		return quoteString(fmt.Sprintf("%v", v))
+	}
+}
+
+// copyValue returns the value in COPY text format for the given column type
+func copyValue(val interface{}, typeName string) string {
+	var str string
+
+	switch v := val.(type) {
+	case nil:
+		return `\N`
+	case bool:
+		if v {
+			return "t"
+		}
+		return "f"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return formatFloat(v)
+	case time.Time:
+		str = formatTime(v, typeName)
+	case []byte:
+		if typeName == "BYTEA" {
+			str = "\\x" + hex.EncodeToString(v)
+		} else {
+			str = string(v)
+		}
+	case string:
+		str = v
+	default:
+		str = fmt.Sprintf("%v", v)
+	}
+
+	return copyEscaper.Replace(str)
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNativeDumpValidate(t *testing.T) {
+	examples := []struct {
+		name string
+		dump NativeDump
+		err  string
+	}{
+		{"no source", NativeDump{}, "table or query is required"},
+		{"table and query", NativeDump{Table: "books", Query: "SELECT 1"}, "table and query options can't be used together"},
+		{"query without target", NativeDump{Query: "SELECT 1"}, "target table name is required for query exports"},
+		{"invalid format", NativeDump{Table: "books", Format: "csv"}, "invalid export format: csv"},
+		{"invalid batch size", NativeDump{Table: "books", BatchSize: -1}, "batch size must be greater than 0"},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			assert.EqualError(t, ex.dump.Validate(), ex.err)
+		})
+	}
+
+	dump := NativeDump{Query: "SELECT 1", Target: "numbers"}
+	assert.NoError(t, dump.Validate())
+	assert.Equal(t, NativeFormatInsert, dump.Format)
+	assert.Equal(t, defaultNativeBatchSize, dump.BatchSize)
+}
+
+func TestSQLLiteral(t *testing.T) {
+	ts := time.Date(2023, 5, 6, 7, 8, 9, 123456000, time.FixedZone("", 3*3600))
+
+	examples := []struct {
+		value    interface{}
+		typeName string
+		expected string
+	}{
+		{nil, "TEXT", "NULL"},
+		{true, "BOOL", "true"},
+		{int64(-42), "INT8", "-42"},
+		{float64(1.5), "FLOAT8", "1.5"},
+		{math.NaN(), "FLOAT8", "'NaN'"},
+		{math.Inf(-1), "FLOAT4", "'-Infinity'"},
+		{"it's", "TEXT", "'it''s'"},
+		{`C:\path`, "TEXT", `'C:\path'`},
+		{[]byte("12.50"), "NUMERIC", "'12.50'"},
+		{[]byte(`{"a": "b'c"}`), "JSONB", `'{"a": "b''c"}'`},
+		{[]byte{0xde, 0xad, 0x00}, "BYTEA", `'\xdead00'`},
+		{ts, "DATE", "'2023-05-06'"},
+		{ts, "TIMESTAMP", "'2023-05-06 07:08:09.123456'"},
+		{ts, "TIMESTAMPTZ", "'2023-05-06 07:08:09.123456+03:00'"},
+		{ts, "TIME", "'07:08:09.123456'"},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.expected, func(t *testing.T) {
+			assert.Equal(t, ex.expected, sqlLiteral(ex.value, ex.typeName))
+		})
+	}
+}
+
+func TestCopyValue(t *testing.T) {
+	examples := []struct {
+		value    interface{}
+		typeName string
+		expected string
+	}{
+		{nil, "TEXT", `\N`},
+		{false, "BOOL", "f"},
+		{int64(7), "INT4", "7"},
+		{math.Inf(1), "FLOAT8", "Infinity"},
+		{"a\tb\nc\\d", "TEXT", `a\tb\nc\\d`},
+		{[]byte{0x01, 0xff}, "BYTEA", `\\x01ff`},
+		{time.Date(2023, 5, 6, 0, 0, 0, 0, time.UTC), "TIMESTAMPTZ", "2023-05-06 00:00:00Z"},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.expected, func(t *testing.T) {
+			assert.Equal(t, ex.expected, copyValue(ex.value, ex.typeName))
+		})
+	}
+}
+
+func TestNativeWriter(t *testing.T) {
+	t.Run("empty copy", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		nw := &nativeWriter{w: buf, format: NativeFormatCopy}
+
+		assert.NoError(t, nw.finish())
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("empty insert", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		nw := &nativeWriter{w: buf, format: NativeFormatInsert, batchSize: 10}
+
+		assert.NoError(t, nw.finish())
+		assert.Equal(t, "", buf.String())
+	})
+}
+
+func testNativeExport(t *testing.T) {
+	export := func(dump NativeDump) string {
+		buf := &bytes.Buffer{}
+		assert.NoError(t, testClient.NativeExport(context.Background(), &dump, buf))
+
+		reader, err := gzip.NewReader(buf)
+		assert.NoError(t, err)
+
+		data, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		out := export(NativeDump{Table: "books", BatchSize: 1000})
+		assert.Contains(t, out, "SET standard_conforming_strings = on;")
+		assert.Contains(t, out, `INSERT INTO "public"."books" ("id", "title", "author_id", "subject_id") VALUES`)
+		assert.Contains(t, out, "(156, 'The Tell-Tale Heart', 115, 9)")
+	})
+
+	t.Run("copy query", func(t *testing.T) {
+		out := export(NativeDump{Query: "SELECT id, title FROM books WHERE id = 156", Target: "archive.books", Format: NativeFormatCopy})
+		assert.Contains(t, out, "COPY \"archive\".\"books\" (\"id\", \"title\") FROM stdin;\n156\tThe Tell-Tale Heart\n\\.\n")
+	})
+
+	t.Run("invalid table", func(t *testing.T) {
+		err := testClient.NativeExport(context.Background(), &NativeDump{Table: "foobar"}, io.Discard)
+		assert.Contains(t, err.Error(), `relation "public.foobar" does not exist`)
+	})
+}
@@ -0,0 +1,283 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// Reconnect intervals of the listener connection after connection loss
+	listenerMinReconnect = 10 * time.Second
+	listenerMaxReconnect = time.Minute
+
+	// Time to wait for the listener connection to be established
+	listenerConnectTimeout = 10 * time.Second
+
+	// Interval of the listener connection health check
+	listenerPingInterval = 90 * time.Second
+
+	// Notifications buffered per subscription, notifications are dropped for slow readers
+	subscriptionBufferSize = 100
+
+	// Maximum length of the channel name, NAMEDATALEN - 1
+	maxChannelNameLength = 63
+)
+
+var errSubscriptionClosed = errors.New("subscription is closed")
+
+// Notification is a payload received on a subscribed channel
+type Notification struct {
+	Channel string    `json:"channel"`
+	Payload string    `json:"payload"`
+	PID     int       `json:"pid"` // Backend that sent the notification
+	Time    time.Time `json:"time"`
+}
+
+// Listener dispatches notifications received on the dedicated session connection
+// to subscriptions. Channels are listened while at least one subscription uses them.
+type Listener struct {
+	conn *pq.Listener
+
+	// Guards subscriptions, held while dispatching notifications
+	mu            sync.Mutex
+	subscriptions map[*Subscription]bool
+	closed        bool
+
+	// Guards channels along with LISTEN / UNLISTEN calls
+	listenMu sync.Mutex
+	channels map[string]int // Number of subscriptions per channel
+}
+
+// Subscription receives notifications of the subscribed channels until closed.
+// C is closed when the subscription or the session connection is closed.
+type Subscription struct {
+	C        chan Notification
+	channels map[string]bool
+	listener *Listener
+}
+
+// validateChannels checks names of the channels to listen on
+func validateChannels(channels []string) error {
+	if len(channels) == 0 {
+		return errors.New("at least one channel is required")
+	}
+	for _, channel := range channels {
+		if channel == "" {
+			return errors.New("channel name is required")
+		}
+		if len(channel) > maxChannelNameLength {
+			return fmt.Errorf("channel name is too long: %v", channel)
+		}
+	}
+	return nil
+}
+
+// newListener opens the listener connection and waits until it's established
+func newListener(connStr string) (*Listener, error) {
+	var once sync.Once
+	connected := make(chan error, 1)
+
+	pqListener := pq.NewListener(connStr, listenerMinReconnect, listenerMaxReconnect, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventConnected:
+			once.Do(func() { connected <- nil })
+		case pq.ListenerEventConnectionAttemptFailed:
+			once.Do(func() { connected <- err })
+		}
+	})
+
+	select {
+	case err := <-connected:
+		if err != nil {
+			pqListener.Close()
+			return nil, err
+		}
+	case <-time.After(listenerConnectTimeout):
+		pqListener.Close()
+		return nil, errors.New("listener connection timed out")
+	}
+
+	l := &Listener{
+		conn:          pqListener,
+		subscriptions: map[*Subscription]bool{},
+		channels:      map[string]int{},
+	}
+	go l.dispatch()
+
+	return l, nil
+}
+
+// dispatch delivers notifications to subscriptions until the listener is closed
+func (l *Listener) dispatch() {
+	for {
+		select {
+		case n, ok := <-l.conn.Notify:
+			if !ok {
+				l.closeSubscriptions()
+				return
+			}
+			// nil is sent after the connection was re-established
+			if n == nil {
+				continue
+			}
+			l.deliver(Notification{Channel: n.Channel, Payload: n.Extra, PID: n.BePid, Time: time.Now().UTC()})
+		case <-time.After(listenerPingInterval):
+			go l.conn.Ping() //nolint
+		}
+	}
+}
+
+func (l *Listener) deliver(n Notification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for sub := range l.subscriptions {
+		if !sub.channels[n.Channel] {
+			continue
+		}
+		select {
+		case sub.C <- n:
+		default:
+		}
+	}
+}
+
+func (l *Listener) closeSubscriptions() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for sub := range l.subscriptions {
+		close(sub.C)
+	}
+	l.subscriptions = map[*Subscription]bool{}
+	l.closed = true
+}
+
+func (l *Listener) subscribe(channels []string) (*Subscription, error) {
+	// LISTEN waits for the server response, so it's not called while holding
+	// the dispatch lock, otherwise pending notifications could block it
+	l.listenMu.Lock()
+	defer l.listenMu.Unlock()
+
+	sub := &Subscription{
+		C:        make(chan Notification, subscriptionBufferSize),
+		channels: map[string]bool{},
+		listener: l,
+	}
+
+	for _, channel := range channels {
+		if sub.channels[channel] {
+			continue
+		}
+		if l.channels[channel] == 0 {
+			if err := l.conn.Listen(channel); err != nil && err != pq.ErrChannelAlreadyOpen {
+				l.release(sub)
+				return nil, err
+			}
+		}
+		sub.channels[channel] = true
+		l.channels[channel]++
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		l.release(sub)
+		return nil, errSubscriptionClosed
+	}
+	l.subscriptions[sub] = true
+
+	return sub, nil
+}
+
+// release stops listening on channels no longer used by other subscriptions
+func (l *Listener) release(sub *Subscription) {
+	for channel := range sub.channels {
+		l.channels[channel]--
+		if l.channels[channel] > 0 {
+			continue
+		}
+		delete(l.channels, channel)
+		l.conn.Unlisten(channel) //nolint
+	}
+}
+
+// Close stops the subscription
+func (sub *Subscription) Close() {
+	l := sub.listener
+
+	l.listenMu.Lock()
+	defer l.listenMu.Unlock()
+
+	l.mu.Lock()
+	active := l.subscriptions[sub]
+	if active {
+		delete(l.subscriptions, sub)
+		close(sub.C)
+	}
+	l.mu.Unlock()
+
+	if active {
+		l.release(sub)
+	}
+}
+
+// Channels returns names of the subscribed channels
+func (sub *Subscription) Channels() []string {
+	channels := []string{}
+	for channel := range sub.channels {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// Subscribe listens on the channels over the dedicated listener connection of the
+// client, the connection is opened with the first subscription and closed along with the client.
+func (client *Client) Subscribe(channels []string) (*Subscription, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return nil, errors.New("LISTEN/NOTIFY is not supported on CockroachDB")
+	}
+	if err := validateChannels(channels); err != nil {
+		return nil, err
+	}
+
+	client.listenerMu.Lock()
+	if client.listenerClosed {
+		client.listenerMu.Unlock()
+		return nil, errNotConnected
+	}
+	if client.listener == nil {
+		l, err := newListener(client.ConnectionString)
+		if err != nil {
+			client.listenerMu.Unlock()
+			return nil, err
+		}
+		client.listener = l
+	}
+	l := client.listener
+	client.listenerMu.Unlock()
+
+	return l.subscribe(channels)
+}
+
+// closeListener closes the listener connection, all subscriptions are closed once
+// the pending notifications are dispatched. Subscriptions are refused afterwards,
+// so no listener connection is opened for the closed client.
+func (client *Client) closeListener() {
+	client.listenerMu.Lock()
+	defer client.listenerMu.Unlock()
+
+	client.listenerClosed = true
+	if client.listener != nil {
+		client.listener.conn.Close() //nolint
+		client.listener = nil
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateChannels(t *testing.T) {
+	assert.NoError(t, validateChannels([]string{"jobs", "Orders.Created"}))
+	assert.EqualError(t, validateChannels(nil), "at least one channel is required")
+	assert.EqualError(t, validateChannels([]string{"jobs", ""}), "channel name is required")
+	assert.EqualError(t, validateChannels([]string{strings.Repeat("a", 64)}), "channel name is too long: "+strings.Repeat("a", 64))
+}
+
+func TestListenerDeliver(t *testing.T) {
+	jobs := &Subscription{C: make(chan Notification, 1), channels: map[string]bool{"jobs": true}}
+	orders := &Subscription{C: make(chan Notification, 1), channels: map[string]bool{"orders": true}}
+
+	l := &Listener{
+		subscriptions: map[*Subscription]bool{jobs: true, orders: true},
+		channels:      map[string]int{"jobs": 1, "orders": 1},
+	}
+	jobs.listener = l
+	orders.listener = l
+
+	l.deliver(Notification{Channel: "jobs", Payload: "1"})
+	// Buffer of the subscription is full, the notification is dropped
+	l.deliver(Notification{Channel: "jobs", Payload: "2"})
+
+	assert.Equal(t, "1", (<-jobs.C).Payload)
+	assert.Empty(t, orders.C)
+
+	l.closeSubscriptions()
+
+	_, ok := <-jobs.C
+	assert.False(t, ok)
+	_, ok = <-orders.C
+	assert.False(t, ok)
+
+	// Closing a subscription of the closed listener is a no-op
+	jobs.Close()
+	assert.Equal(t, 1, l.channels["jobs"])
+
+	_, err := l.subscribe([]string{})
+	assert.Equal(t, errSubscriptionClosed, err)
+}
+
+func TestSubscribeClosedClient(t *testing.T) {
+	db, err := sqlx.Open("postgres", "host=localhost dbname=pgweb sslmode=disable")
+	assert.NoError(t, err)
+
+	cl := &Client{db: db}
+	assert.NoError(t, cl.Close())
+
+	// No listener connection is opened for the closed client
+	sub, err := cl.Subscribe([]string{"jobs"})
+	assert.Equal(t, errNotConnected, err)
+	assert.Nil(t, sub)
+	assert.Nil(t, cl.listener)
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	PrivilegeObjectTable    = "table"
+	PrivilegeObjectSchema   = "schema"
+	PrivilegeObjectFunction = "function"
+)
+
+// Grants of the object, each query returns grantee, grantor, privilege_type
+// and is_grantable columns. PUBLIC grants use the PUBLIC grantee name.
+const (
+	// Objects without explicit grants have NULL acl, acldefault returns the built-in privileges
+	aclGrantsQuery = `SELECT
+    CASE WHEN (a.acl).grantee = 0 THEN 'PUBLIC' ELSE pg_get_userbyid((a.acl).grantee)::text END AS grantee,
+    pg_get_userbyid((a.acl).grantor)::text AS grantor,
+    (a.acl).privilege_type AS privilege_type,
+    (a.acl).is_grantable AS is_grantable
+  FROM
+    (SELECT aclexplode(COALESCE(%s, acldefault('%s', %s))) AS acl FROM %s WHERE %s) a`
+)
+
+// privilegesQuery returns the effective privileges query for the object
+func privilegesQuery(objectType string, object string) (string, []interface{}, error) {
+	if object == "" {
+		return "", nil, errors.New("object name is required")
+	}
+
+	var grants string
+	var args []interface{}
+
+	switch objectType {
+	case PrivilegeObjectTable:
+		schema, table := getSchemaAndTable(object)
+		grants = fmt.Sprintf(aclGrantsQuery, "c.relacl", "r", "c.relowner",
+			"pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace", "n.nspname = $1 AND c.relname = $2")
+		args = []interface{}{schema, table}
+	case PrivilegeObjectSchema:
+		grants = fmt.Sprintf(aclGrantsQuery, "nspacl", "n", "nspowner", "pg_namespace", "nspname = $1")
+		args = []interface{}{object}
+	case PrivilegeObjectFunction:
+		// Functions are identified by oid since names could be overloaded
+		oid, err := strconv.Atoi(object)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid function oid: %v", object)
+		}
+		grants = fmt.Sprintf(aclGrantsQuery, "proacl", "f", "proowner", "pg_proc", "oid = $1")
+		args = []interface{}{oid}
+	default:
+		return "", nil, fmt.Errorf("invalid object type: %v", objectType)
+	}
+
+	return fmt.Sprintf(statements.Privileges, grants), args, nil
+}
+
+// Roles returns all roles with their attributes and memberships
+func (client *Client) Roles() (*Result, error) {
+	if client.serverType == cockroachType {
+		return nil, errors.New("roles browser is not supported on CockroachDB")
+	}
+	return client.query(versionedStatement(statements.Roles, getMajorMinorVersionString(client.serverVersion)))
+}
+
+// Privileges returns effective privileges of every role on the table, schema or function.
+// Privileges are inherited through memberships of roles with the inherit attribute.
+func (client *Client) Privileges(objectType string, object string) (*Result, error) {
+	if client.serverType == cockroachType {
+		return nil, errors.New("privileges browser is not supported on CockroachDB")
+	}
+
+	query, args, err := privilegesQuery(objectType, object)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.query(query, args...)
+}
+
+// DefaultPrivileges returns privileges applied to objects created in the future
+func (client *Client) DefaultPrivileges() (*Result, error) {
+	if client.serverType == cockroachType {
+		return nil, errors.New("privileges browser is not supported on CockroachDB")
+	}
+	return client.query(statements.DefaultPrivileges)
+}
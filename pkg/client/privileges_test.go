@@ -0,0 +1,34 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivilegesQuery(t *testing.T) {
+	query, args, err := privilegesQuery(PrivilegeObjectTable, "books")
+	require.NoError(t, err)
+	assert.Contains(t, query, "aclexplode(COALESCE(c.relacl, acldefault('r', c.relowner))) AS acl FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND c.relname = $2")
+	assert.Equal(t, []interface{}{"public", "books"}, args)
+
+	query, args, err = privilegesQuery(PrivilegeObjectSchema, "sales")
+	require.NoError(t, err)
+	assert.Contains(t, query, "aclexplode(COALESCE(nspacl, acldefault('n', nspowner))) AS acl FROM pg_namespace WHERE nspname = $1")
+	assert.Equal(t, []interface{}{"sales"}, args)
+
+	query, args, err = privilegesQuery(PrivilegeObjectFunction, "16384")
+	require.NoError(t, err)
+	assert.Contains(t, query, "aclexplode(COALESCE(proacl, acldefault('f', proowner))) AS acl FROM pg_proc WHERE oid = $1")
+	assert.Equal(t, []interface{}{16384}, args)
+
+	_, _, err = privilegesQuery(PrivilegeObjectFunction, "now")
+	assert.EqualError(t, err, "invalid function oid: now")
+
+	_, _, err = privilegesQuery("view", "books")
+	assert.EqualError(t, err, "invalid object type: view")
+
+	_, _, err = privilegesQuery(PrivilegeObjectTable, "")
+	assert.EqualError(t, err, "object name is required")
+}
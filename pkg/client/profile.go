@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	// Default share of table pages read by the profile
+	defaultProfileSamplePercent = 10
+
+	// SQLSTATE returned when the column type has no equality or ordering operator
+	errCodeUndefinedFunction = "42883"
+)
+
+// ProfileOptions contains sampling options of the table profile
+type ProfileOptions struct {
+	SamplePercent float64 // Percent of table pages to read, the whole table is read when 100
+	Seed          int64   // Seed of the sample, the same seed returns the same sample of unchanged table
+}
+
+// Validate checks the options and fills in defaults
+func (opts *ProfileOptions) Validate() error {
+	if opts.SamplePercent == 0 {
+		opts.SamplePercent = defaultProfileSamplePercent
+	}
+	if opts.SamplePercent < 0 || opts.SamplePercent > 100 {
+		return fmt.Errorf("invalid sample percent: %v", opts.SamplePercent)
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano() % 1000000
+	}
+	return nil
+}
+
+// ColumnProfile contains the values profile of a single column.
+// Distinct count and min/max values are missing when the column type could not be compared.
+type ColumnProfile struct {
+	Name     string  `json:"name"`
+	DataType string  `json:"data_type"`
+	Rows     int64   `json:"rows"`
+	Nulls    int64   `json:"nulls"`
+	Distinct *int64  `json:"distinct"`
+	Min      *string `json:"min"`
+	Max      *string `json:"max"`
+}
+
+// TableProfile contains profiles of all table columns
+type TableProfile struct {
+	Table         string          `json:"table"`
+	SamplePercent float64         `json:"sample_percent"`
+	Seed          int64           `json:"seed"`
+	Columns       []ColumnProfile `json:"columns"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// ProfileProgress is reported after each profiled column
+type ProfileProgress struct {
+	Column string `json:"column"`
+	Done   int    `json:"done"`
+	Total  int    `json:"total"`
+}
+
+// ColumnStats returns planner statistics of the table columns
+func (client *Client) ColumnStats(table string) (*Result, error) {
+	if client.serverType == cockroachType {
+		return nil, errors.New("column statistics are not supported on CockroachDB")
+	}
+
+	schema, name := getSchemaAndTable(table)
+	return client.query(statements.ColumnStats, schema, name)
+}
+
+// TableProfile returns the cached profile of the table
+func (client *Client) TableProfile(table string) *TableProfile {
+	client.profilesMu.Lock()
+	defer client.profilesMu.Unlock()
+
+	return client.profiles[quoteTable(table)]
+}
+
+// ProfileTable computes row, null and distinct counts along with min/max values of
+// every column over a sample of the table. The profile is cached until the next run.
+func (client *Client) ProfileTable(ctx context.Context, table string, opts ProfileOptions, progress func(ProfileProgress)) (*TableProfile, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return nil, errors.New("table profile is not supported on CockroachDB")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if major, minor := getMajorMinorVersion(client.serverVersion); opts.SamplePercent < 100 && (major < 9 || (major == 9 && minor < 5)) {
+		return nil, fmt.Errorf("table sampling is not supported on PostgreSQL %v", client.serverVersion)
+	}
+
+	defer func() {
+		client.lastQueryTime = time.Now().UTC()
+	}()
+
+	schema, name := getSchemaAndTable(table)
+
+	columns := []struct {
+		Name     string `db:"name"`
+		DataType string `db:"data_type"`
+	}{}
+	err := client.db.SelectContext(ctx, &columns, `SELECT a.attname AS name, pg_catalog.format_type(a.atttypid, a.atttypmod) AS data_type
+FROM pg_catalog.pg_attribute a
+WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`, quoteTable(table))
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &TableProfile{
+		Table:         schema + "." + name,
+		SamplePercent: opts.SamplePercent,
+		Seed:          opts.Seed,
+		Columns:       []ColumnProfile{},
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	for i, column := range columns {
+		result, err := client.profileColumn(ctx, table, column.Name, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.DataType = column.DataType
+		profile.Columns = append(profile.Columns, *result)
+
+		if progress != nil {
+			progress(ProfileProgress{Column: column.Name, Done: i + 1, Total: len(columns)})
+		}
+	}
+
+	client.profilesMu.Lock()
+	if client.profiles == nil {
+		client.profiles = map[string]*TableProfile{}
+	}
+	client.profiles[quoteTable(table)] = profile
+	client.profilesMu.Unlock()
+
+	return profile, nil
+}
+
+func (client *Client) profileColumn(ctx context.Context, table string, column string, opts ProfileOptions) (*ColumnProfile, error) {
+	result := &ColumnProfile{Name: column}
+
+	var min, max sql.NullString
+	var distinct int64
+
+	err := client.db.QueryRowContext(ctx, profileColumnQuery(table, column, opts, true)).
+		Scan(&result.Rows, &result.Nulls, &distinct, &min, &max)
+	if err == nil {
+		result.Distinct = &distinct
+		if min.Valid {
+			result.Min = &min.String
+		}
+		if max.Valid {
+			result.Max = &max.String
+		}
+		return result, nil
+	}
+
+	// Types like json or point could not be compared, only count their values
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != errCodeUndefinedFunction {
+		return nil, err
+	}
+
+	err = client.db.QueryRowContext(ctx, profileColumnQuery(table, column, opts, false)).
+		Scan(&result.Rows, &result.Nulls)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// profileColumnQuery returns the column profile query. The same seed is used
+// for every column, so all of them are profiled over the same sample.
+func profileColumnQuery(table string, column string, opts ProfileOptions, comparable bool) string {
+	source := quoteTable(table)
+	if opts.SamplePercent < 100 {
+		source += fmt.Sprintf(
+			" TABLESAMPLE SYSTEM (%s) REPEATABLE (%d)",
+			strconv.FormatFloat(opts.SamplePercent, 'f', -1, 64),
+			opts.Seed,
+		)
+	}
+
+	sample := fmt.Sprintf("WITH sample AS (SELECT %s AS value FROM %s) ", pq.QuoteIdentifier(column), source)
+	if !comparable {
+		return sample + "SELECT count(*), count(*) - count(value) FROM sample"
+	}
+
+	return sample + `SELECT
+  count(*),
+  count(*) - count(value),
+  count(DISTINCT value),
+  (SELECT value::text FROM sample WHERE value IS NOT NULL ORDER BY value LIMIT 1),
+  (SELECT value::text FROM sample WHERE value IS NOT NULL ORDER BY value DESC LIMIT 1)
+FROM sample`
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileOptionsValidate(t *testing.T) {
+	opts := ProfileOptions{}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, float64(10), opts.SamplePercent)
+	assert.NotZero(t, opts.Seed)
+
+	opts = ProfileOptions{SamplePercent: 100, Seed: 7}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, int64(7), opts.Seed)
+
+	opts = ProfileOptions{SamplePercent: 120}
+	assert.EqualError(t, opts.Validate(), "invalid sample percent: 120")
+}
+
+func TestProfileColumnQuery(t *testing.T) {
+	query := profileColumnQuery("sales.orders", "Total", ProfileOptions{SamplePercent: 2.5, Seed: 42}, true)
+	assert.Contains(t, query, `WITH sample AS (SELECT "Total" AS value FROM "sales"."orders" TABLESAMPLE SYSTEM (2.5) REPEATABLE (42))`)
+	assert.Contains(t, query, "count(DISTINCT value)")
+	assert.Contains(t, query, "ORDER BY value DESC LIMIT 1")
+
+	query = profileColumnQuery("orders", "payload", ProfileOptions{SamplePercent: 100, Seed: 42}, false)
+	assert.Equal(t, `WITH sample AS (SELECT "payload" AS value FROM "public"."orders") SELECT count(*), count(*) - count(value) FROM sample`, query)
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	ReplicationRolePrimary = "primary"
+	ReplicationRoleStandby = "standby"
+)
+
+// ReplicationStatus contains the replication state of the server.
+// Primary servers report connected standbys and replication slots,
+// standby servers report the WAL receive and replay positions.
+type ReplicationStatus struct {
+	Role     string  `json:"role"`
+	Standbys *Result `json:"standbys,omitempty"`
+	Slots    *Result `json:"slots,omitempty"`
+	Receiver *Result `json:"receiver,omitempty"`
+}
+
+// replicationQueries holds replication statements for a specific server version
+type replicationQueries struct {
+	standbys string
+	slots    string // Empty when the server does not support replication slots
+	receiver string
+}
+
+// getReplicationQueries picks the replication statements for the server version.
+// PostgreSQL 10 renamed xlog functions to wal and location columns to lsn.
+func getReplicationQueries(version string) (*replicationQueries, error) {
+	major, minor := getMajorMinorVersion(version)
+	if major < 9 || (major == 9 && minor < 2) {
+		return nil, fmt.Errorf("replication status is not supported on PostgreSQL %v", version)
+	}
+
+	key := getMajorMinorVersionString(version)
+	queries := &replicationQueries{
+		standbys: versionedStatement(statements.ReplicationStandbys, key),
+		receiver: versionedStatement(statements.ReplicationReceiver, key),
+	}
+	if major > 9 || minor >= 4 {
+		queries.slots = versionedStatement(statements.ReplicationSlots, key)
+	}
+
+	return queries, nil
+}
+
+func versionedStatement(queries map[string]string, version string) string {
+	if query, ok := queries[version]; ok {
+		return query
+	}
+	return queries["default"]
+}
+
+// Replication returns the replication status of the server
+func (client *Client) Replication() (*ReplicationStatus, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return nil, errors.New("replication status is not supported on CockroachDB")
+	}
+
+	queries, err := getReplicationQueries(client.serverVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	var inRecovery bool
+	if err := client.db.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return nil, err
+	}
+
+	status := &ReplicationStatus{Role: ReplicationRolePrimary}
+
+	// WAL position functions of the primary are not available during recovery
+	if inRecovery {
+		status.Role = ReplicationRoleStandby
+		status.Receiver, err = client.query(queries.receiver)
+		if err != nil {
+			return nil, err
+		}
+		return status, nil
+	}
+
+	status.Standbys, err = client.query(queries.standbys)
+	if err != nil {
+		return nil, err
+	}
+
+	if queries.slots != "" {
+		status.Slots, err = client.query(queries.slots)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+func TestGetReplicationQueries(t *testing.T) {
+	_, err := getReplicationQueries("9.1.24")
+	assert.EqualError(t, err, "replication status is not supported on PostgreSQL 9.1.24")
+
+	queries, err := getReplicationQueries("9.3.25")
+	require.NoError(t, err)
+	assert.Contains(t, queries.standbys, "pg_xlog_location_diff")
+	assert.Contains(t, queries.receiver, "pg_last_xlog_receive_location")
+	assert.Empty(t, queries.slots)
+
+	queries, err = getReplicationQueries("9.6.24")
+	require.NoError(t, err)
+	assert.Contains(t, queries.standbys, "replay_location")
+	assert.Equal(t, statements.ReplicationSlots["9.6"], queries.slots)
+
+	queries, err = getReplicationQueries("15.4")
+	require.NoError(t, err)
+	assert.Equal(t, statements.ReplicationStandbys["default"], queries.standbys)
+	assert.Equal(t, statements.ReplicationSlots["default"], queries.slots)
+	assert.Contains(t, queries.receiver, "pg_last_wal_replay_lsn")
+}
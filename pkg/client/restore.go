@@ -0,0 +1,149 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+const (
+	RestoreFormatPlain   = "plain"
+	RestoreFormatArchive = "archive"
+)
+
+var (
+	// Custom format archives produced by pg_dump start with this signature
+	archiveSignature = []byte("PGDMP")
+
+	// Tar archives have this signature at the tarSignatureOffset
+	tarSignature       = []byte("ustar")
+	tarSignatureOffset = 257
+
+	gzipSignature = []byte{0x1f, 0x8b}
+)
+
+// Restore represents a database restore from a dump file.
+// Plain SQL dumps are executed with psql, custom and tar archives with pg_restore.
+type Restore struct {
+	Format            string // Input format: plain or archive, detected when empty
+	Clean             bool   // Drop database objects before recreating them, archives only
+	SingleTransaction bool   // Run the restore as a single transaction
+
+	binary string // Path of the psql or pg_restore binary selected for the server
+}
+
+// Detect determines the dump format from the input signature when it's not set.
+// The returned reader must be used instead of the original input.
+func (r *Restore) Detect(input io.Reader) (io.Reader, error) {
+	buf := bufio.NewReaderSize(input, 4096)
+
+	header, err := buf.Peek(tarSignatureOffset + len(tarSignature))
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if len(header) == 0 {
+		return nil, fmt.Errorf("dump file is empty")
+	}
+
+	isArchive := bytes.HasPrefix(header, archiveSignature) ||
+		(len(header) >= tarSignatureOffset+len(tarSignature) && bytes.Equal(header[tarSignatureOffset:], tarSignature))
+
+	switch r.Format {
+	case "":
+		r.Format = RestoreFormatPlain
+		if isArchive {
+			r.Format = RestoreFormatArchive
+		}
+	case RestoreFormatPlain, RestoreFormatArchive:
+	default:
+		return nil, fmt.Errorf("invalid restore format: %v", r.Format)
+	}
+
+	// Plain dumps made by pgweb are gzip-compressed, psql expects raw SQL
+	if r.Format == RestoreFormatPlain && bytes.HasPrefix(header, gzipSignature) {
+		return gzip.NewReader(buf)
+	}
+
+	return buf, nil
+}
+
+// Validate checks availability and version of the restore CLI and selects
+// the binary compatible with the server version
+func (r *Restore) Validate(serverVersion string) error {
+	path, err := findClientBinary(r.commandName(), serverVersion)
+	if err != nil {
+		return err
+	}
+
+	r.binary = path
+	return nil
+}
+
+// Import streams the dump into the database, reporting command output to the writer
+func (r *Restore) Import(ctx context.Context, connstr string, input io.Reader, output io.Writer) error {
+	if str, err := removeUnsupportedOptions(connstr); err != nil {
+		return err
+	} else {
+		connstr = str
+	}
+
+	opts := append(r.options(), "--dbname", connstr)
+
+	cmd := exec.CommandContext(ctx, r.command(), opts...)
+	cmd.Stdin = input
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%s command failed: %s", r.commandName(), err.Error())
+	}
+	return nil
+}
+
+func (r *Restore) command() string {
+	if r.binary != "" {
+		return r.binary
+	}
+	return r.commandName()
+}
+
+func (r *Restore) commandName() string {
+	if r.Format == RestoreFormatArchive {
+		return "pg_restore"
+	}
+	return "psql"
+}
+
+// options returns the restore command line arguments, input is always read from stdin
+func (r *Restore) options() []string {
+	if r.Format == RestoreFormatArchive {
+		opts := []string{
+			"--no-owner", // skip restoration of object ownership
+			"--verbose",  // report progress on every restored object
+		}
+		if r.Clean {
+			opts = append(opts, "--clean", "--if-exists")
+		}
+		if r.SingleTransaction {
+			opts = append(opts, "--single-transaction")
+		}
+		return opts
+	}
+
+	opts := []string{
+		"--no-psqlrc",              // ignore user configuration
+		"--set", "ON_ERROR_STOP=1", // abort on the first failed statement
+		"--file", "-",
+	}
+	if r.SingleTransaction {
+		opts = append(opts, "--single-transaction")
+	}
+	return opts
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreDetect(t *testing.T) {
+	gzipped := &bytes.Buffer{}
+	zw := gzip.NewWriter(gzipped)
+	zw.Write([]byte("SELECT 1;\n")) //nolint
+	zw.Close()
+
+	tarHeader := make([]byte, 512)
+	copy(tarHeader[257:], "ustar")
+
+	examples := []struct {
+		name    string
+		format  string
+		input   []byte
+		command string
+		output  string
+		err     string
+	}{
+		{"plain sql", "", []byte("SELECT 1;\n"), "psql", "SELECT 1;\n", ""},
+		{"gzipped sql", "", gzipped.Bytes(), "psql", "SELECT 1;\n", ""},
+		{"custom archive", "", []byte("PGDMP\x01\x0e"), "pg_restore", "PGDMP\x01\x0e", ""},
+		{"tar archive", "", tarHeader, "pg_restore", string(tarHeader), ""},
+		{"forced plain", RestoreFormatPlain, []byte("PGDMP"), "psql", "PGDMP", ""},
+		{"invalid format", "directory", []byte("SELECT 1;"), "", "", "invalid restore format: directory"},
+		{"empty input", "", []byte{}, "", "", "dump file is empty"},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			restore := Restore{Format: ex.format}
+
+			reader, err := restore.Detect(bytes.NewReader(ex.input))
+			if ex.err != "" {
+				assert.EqualError(t, err, ex.err)
+				return
+			}
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, ex.output, string(data))
+			assert.Equal(t, ex.command, restore.command())
+		})
+	}
+}
+
+func TestRestoreOptions(t *testing.T) {
+	restore := Restore{Format: RestoreFormatPlain, Clean: true, SingleTransaction: true}
+	assert.Equal(t, "--no-psqlrc --set ON_ERROR_STOP=1 --file - --single-transaction", strings.Join(restore.options(), " "))
+
+	restore = Restore{Format: RestoreFormatArchive}
+	assert.Equal(t, "--no-owner --verbose", strings.Join(restore.options(), " "))
+
+	restore = Restore{Format: RestoreFormatArchive, Clean: true, SingleTransaction: true}
+	assert.Equal(t, "--no-owner --verbose --clean --if-exists --single-transaction", strings.Join(restore.options(), " "))
+}
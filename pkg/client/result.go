@@ -2,9 +2,7 @@ package client
 
 import (
 	"bytes"
-	"encoding/csv"
 	"encoding/json"
-	"fmt"
 	"log"
 	"math"
 	"strconv"
@@ -20,6 +18,11 @@ const (
 	ObjTypeMaterializedView = "materialized_view"
 	ObjTypeSequence         = "sequence"
 	ObjTypeFunction         = "function"
+	ObjTypePartitionedTable = "partitioned_table"
+	ObjTypeForeignTable     = "foreign_table"
+	ObjTypeTrigger          = "trigger"
+	ObjTypeType             = "type"
+	ObjTypeExtension        = "extension"
 )
 
 type (
@@ -73,8 +76,11 @@ type (
 	}
 
 	Object struct {
-		OID  string `json:"oid"`
-		Name string `json:"name"`
+		OID        string   `json:"oid"`
+		Name       string   `json:"name"`
+		Schema     string   `json:"schema,omitempty"`     // 分区所在的 schema
+		Table      string   `json:"table,omitempty"`      // 触发器所属的表
+		Partitions []Object `json:"partitions,omitempty"` // 分区表的分区
 	}
 
 	// 对象
@@ -84,6 +90,11 @@ type (
 		MaterializedViews []Object `json:"materialized_view"`
 		Functions         []Object `json:"function"`
 		Sequences         []Object `json:"sequence"`
+		PartitionedTables []Object `json:"partitioned_table"`
+		ForeignTables     []Object `json:"foreign_table"`
+		Triggers          []Object `json:"trigger"`
+		Types             []Object `json:"type"`
+		Extensions        []Object `json:"extension"`
 	}
 )
 
@@ -130,6 +141,37 @@ func (res *Result) PostProcess() {
 	}
 }
 
+// Size returns the approximate number of bytes used by the result rows
+func (res *Result) Size() int64 {
+	var size int64
+	for _, row := range res.Rows {
+		size += row.Size()
+	}
+	return size
+}
+
+// Size returns the approximate number of bytes used by the row values
+func (row Row) Size() int64 {
+	// Slice header and interface values
+	size := int64(24 + 16*len(row))
+
+	for _, val := range row {
+		switch v := val.(type) {
+		case string:
+			size += int64(len(v))
+		case []byte:
+			size += int64(len(v))
+		case time.Time:
+			size += 24
+		case nil:
+		default:
+			size += 8
+		}
+	}
+
+	return size
+}
+
 func (res *Result) Format() []map[string]interface{} {
 	items := make([]map[string]interface{}, len(res.Rows))
 
@@ -148,34 +190,11 @@ func (res *Result) Format() []map[string]interface{} {
 // 将结果转换为 CSV 的字节数组
 func (res *Result) CSV() []byte {
 	buff := &bytes.Buffer{}
-	writer := csv.NewWriter(buff)
 
-	if err := writer.Write(res.Columns); err != nil {
+	if err := res.WriteCSV(buff, CSVOptions{}); err != nil {
 		log.Printf("result csv write error: %v\n", err)
 	}
 
-	for _, row := range res.Rows {
-		record := make([]string, len(res.Columns))
-
-		for i, item := range row {
-			switch v := item.(type) {
-			case time.Time:
-				record[i] = v.Format("2006-01-02 15:04:05")
-			case nil:
-				record[i] = ""
-			default:
-				record[i] = fmt.Sprintf("%v", item)
-			}
-		}
-
-		err := writer.Write(record)
-		if err != nil {
-			fmt.Println(err)
-			break
-		}
-	}
-
-	writer.Flush()
 	return buff.Bytes()
 }
 
@@ -192,14 +211,39 @@ func (res *Result) JSON() []byte {
 	return data
 }
 
+// ObjectsFromResult groups objects by schema. Partitions are nested under
+// their partitioned table, even when they live in a different schema.
 func ObjectsFromResult(res *Result) map[string]*Objects {
 	objects := map[string]*Objects{}
 
+	// 分区按照父表分组
+	names := map[string]string{}
+	partitions := map[string][]Object{}
+	for _, row := range res.Rows {
+		names[row[0].(string)] = row[2].(string)
+	}
+	for _, row := range res.Rows {
+		if parent := objectParent(row); parent != "" && isPartitionType(row[3].(string)) {
+			if _, ok := names[parent]; ok {
+				partitions[parent] = append(partitions[parent], Object{OID: row[0].(string), Name: row[2].(string), Schema: row[1].(string)})
+			}
+		}
+	}
+
+	var withPartitions func(obj Object) Object
+	withPartitions = func(obj Object) Object {
+		for _, partition := range partitions[obj.OID] {
+			obj.Partitions = append(obj.Partitions, withPartitions(partition))
+		}
+		return obj
+	}
+
 	for _, row := range res.Rows {
 		oid := row[0].(string)
 		schema := row[1].(string)
 		name := row[2].(string)
 		objectType := row[3].(string)
+		parent := objectParent(row)
 
 		if objects[schema] == nil {
 			objects[schema] = &Objects{
@@ -208,9 +252,19 @@ func ObjectsFromResult(res *Result) map[string]*Objects {
 				MaterializedViews: []Object{},
 				Functions:         []Object{},
 				Sequences:         []Object{},
+				PartitionedTables: []Object{},
+				ForeignTables:     []Object{},
+				Triggers:          []Object{},
+				Types:             []Object{},
+				Extensions:        []Object{},
 			}
 		}
 
+		// 分区已经嵌套在父表中
+		if _, ok := names[parent]; ok && parent != "" && isPartitionType(objectType) {
+			continue
+		}
+
 		obj := Object{OID: oid, Name: name}
 
 		switch objectType {
@@ -224,8 +278,32 @@ func ObjectsFromResult(res *Result) map[string]*Objects {
 			objects[schema].Functions = append(objects[schema].Functions, obj)
 		case ObjTypeSequence:
 			objects[schema].Sequences = append(objects[schema].Sequences, obj)
+		case ObjTypePartitionedTable:
+			objects[schema].PartitionedTables = append(objects[schema].PartitionedTables, withPartitions(obj))
+		case ObjTypeForeignTable:
+			objects[schema].ForeignTables = append(objects[schema].ForeignTables, obj)
+		case ObjTypeTrigger:
+			obj.Table = names[parent]
+			objects[schema].Triggers = append(objects[schema].Triggers, obj)
+		case ObjTypeType:
+			objects[schema].Types = append(objects[schema].Types, obj)
+		case ObjTypeExtension:
+			objects[schema].Extensions = append(objects[schema].Extensions, obj)
 		}
 	}
 
 	return objects
 }
+
+// objectParent returns oid of the partitioned table or the table of the trigger
+func objectParent(row Row) string {
+	if len(row) < 7 {
+		return ""
+	}
+	parent, _ := row[6].(string)
+	return parent
+}
+
+func isPartitionType(objectType string) bool {
+	return objectType == ObjTypeTable || objectType == ObjTypePartitionedTable || objectType == ObjTypeForeignTable
+}
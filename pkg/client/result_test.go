@@ -137,3 +137,51 @@ func TestResultFormat(t *testing.T) {
 
 	assert.Equal(t, expected, result.Format())
 }
+
+func TestResultSize(t *testing.T) {
+	assert.Equal(t, int64(24+16*3+5+8), Row{"books", int64(1), nil}.Size())
+
+	result := Result{
+		Columns: []string{"id", "title"},
+		Rows:    []Row{{int64(1), "one"}, {int64(2), []byte("three")}},
+	}
+	assert.Equal(t, int64(2*(24+16*2+8)+3+5), result.Size())
+	assert.Equal(t, int64(0), (&Result{}).Size())
+}
+
+func TestObjectsFromResult(t *testing.T) {
+	result := &Result{
+		Columns: []string{"oid", "schema", "name", "type", "owner", "comment", "parent"},
+		Rows: []Row{
+			{"1", "archive", "events_2023", ObjTypeTable, "postgres", nil, "3"},
+			{"2", "public", "books", ObjTypeTable, "postgres", nil, nil},
+			{"3", "public", "events", ObjTypePartitionedTable, "postgres", nil, nil},
+			{"4", "public", "events_2024", ObjTypePartitionedTable, "postgres", nil, "3"},
+			{"5", "public", "events_2024_01", ObjTypeTable, "postgres", nil, "4"},
+			{"6", "public", "remote_books", ObjTypeForeignTable, "postgres", nil, nil},
+			{"7", "public", "sync_stock", ObjTypeTrigger, "postgres", nil, "2"},
+			{"8", "public", "mood", ObjTypeType, "postgres", nil, nil},
+			{"9", "public", "pg_trgm", ObjTypeExtension, "postgres", nil, nil},
+			{"10", "public", "orphan", ObjTypeTable, "postgres", nil, "42"},
+		},
+	}
+
+	objects := ObjectsFromResult(result)
+
+	assert.Empty(t, objects["archive"].Tables)
+	assert.Equal(t, []Object{{OID: "2", Name: "books"}, {OID: "10", Name: "orphan"}}, objects["public"].Tables)
+	assert.Equal(t, []Object{
+		{
+			OID:  "3",
+			Name: "events",
+			Partitions: []Object{
+				{OID: "1", Name: "events_2023", Schema: "archive"},
+				{OID: "4", Name: "events_2024", Schema: "public", Partitions: []Object{{OID: "5", Name: "events_2024_01", Schema: "public"}}},
+			},
+		},
+	}, objects["public"].PartitionedTables)
+	assert.Equal(t, []Object{{OID: "6", Name: "remote_books"}}, objects["public"].ForeignTables)
+	assert.Equal(t, []Object{{OID: "7", Name: "sync_stock", Table: "books"}}, objects["public"].Triggers)
+	assert.Equal(t, []Object{{OID: "8", Name: "mood"}}, objects["public"].Types)
+	assert.Equal(t, []Object{{OID: "9", Name: "pg_trgm"}}, objects["public"].Extensions)
+}
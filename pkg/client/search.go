@@ -0,0 +1,88 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
+var (
+	// Object types returned by the search
+	searchTypes = map[string]bool{
+		"schema":            true,
+		"table":             true,
+		"partitioned_table": true,
+		"view":              true,
+		"materialized_view": true,
+		"foreign_table":     true,
+		"sequence":          true,
+		"column":            true,
+		"function":          true,
+		"index":             true,
+		"constraint":        true,
+	}
+
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+// SearchOptions contains parameters of the object search
+type SearchOptions struct {
+	Query string   // Text to look for in names and comments
+	Types []string // Object types to search, all types when empty
+	Limit int      // Maximum number of hits
+}
+
+// Validate checks the search options and fills in defaults
+func (opts *SearchOptions) Validate() error {
+	opts.Query = strings.TrimSpace(opts.Query)
+	if opts.Query == "" {
+		return errors.New("search query is required")
+	}
+
+	for _, t := range opts.Types {
+		if !searchTypes[t] {
+			return fmt.Errorf("invalid object type: %v", t)
+		}
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = defaultSearchLimit
+	}
+	if opts.Limit < 0 || opts.Limit > maxSearchLimit {
+		return fmt.Errorf("limit must be between 1 and %v", maxSearchLimit)
+	}
+
+	return nil
+}
+
+// escapeLike escapes wildcard characters so the text is matched literally by LIKE
+func escapeLike(str string) string {
+	return likeEscaper.Replace(str)
+}
+
+// Search looks for objects with names or comments containing the query text.
+// Hits are ranked by exact name match, name prefix, name substring and comment match.
+func (client *Client) Search(opts SearchOptions) (*Result, error) {
+	if client.serverType == cockroachType {
+		return nil, errors.New("search is not supported on CockroachDB")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	var types interface{}
+	if len(opts.Types) > 0 {
+		types = pq.Array(opts.Types)
+	}
+
+	return client.query(statements.Search, opts.Query, escapeLike(opts.Query), types, opts.Limit)
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `customer\_id`, escapeLike("customer_id"))
+	assert.Equal(t, `100\%`, escapeLike("100%"))
+	assert.Equal(t, `a\\b`, escapeLike(`a\b`))
+}
+
+func TestSearchOptionsValidate(t *testing.T) {
+	opts := SearchOptions{Query: "  books "}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, "books", opts.Query)
+	assert.Equal(t, 100, opts.Limit)
+
+	examples := []struct {
+		opts SearchOptions
+		err  string
+	}{
+		{opts: SearchOptions{Query: " "}, err: "search query is required"},
+		{opts: SearchOptions{Query: "books", Types: []string{"column", "trigger"}}, err: "invalid object type: trigger"},
+		{opts: SearchOptions{Query: "books", Limit: 5000}, err: "limit must be between 1 and 1000"},
+		{opts: SearchOptions{Query: "books", Types: []string{"table", "column"}, Limit: 10}},
+	}
+
+	for _, ex := range examples {
+		err := ex.opts.Validate()
+		if ex.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, ex.err)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package client
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	SettingSourceDefault     = "default"      // Built-in default value
+	SettingSourceConfigFile  = "config_file"  // postgresql.conf or included files
+	SettingSourceAlterSystem = "alter_system" // postgresql.auto.conf written by ALTER SYSTEM
+	SettingSourceDatabase    = "database"     // ALTER DATABASE / ALTER ROLE ... SET
+	SettingSourceSession     = "session"      // SET in the session or connection parameters
+	SettingSourceOther       = "other"        // Command line, environment and server overrides
+
+	// File written by ALTER SYSTEM
+	autoConfFile = "postgresql.auto.conf"
+)
+
+var settingSources = map[string]bool{
+	SettingSourceDefault:     true,
+	SettingSourceConfigFile:  true,
+	SettingSourceAlterSystem: true,
+	SettingSourceDatabase:    true,
+	SettingSourceSession:     true,
+	SettingSourceOther:       true,
+}
+
+// Setting is a server setting along with the origin of its current value
+type Setting struct {
+	Name           string  `json:"name" db:"name"`
+	Setting        string  `json:"setting" db:"setting"`
+	Unit           *string `json:"unit" db:"unit"`
+	Category       string  `json:"-" db:"category"`
+	Description    string  `json:"description" db:"short_desc"`
+	Context        string  `json:"context" db:"context"` // When the setting could be changed, e.g. postmaster requires a restart
+	Type           string  `json:"type" db:"vartype"`
+	Source         string  `json:"source" db:"source"` // Raw pg_settings source
+	SourceType     string  `json:"source_type" db:"-"`
+	BootValue      *string `json:"boot_value" db:"boot_val"`
+	ResetValue     *string `json:"reset_value" db:"reset_val"`
+	SourceFile     *string `json:"source_file" db:"sourcefile"` // Only visible to superusers and pg_read_all_settings members
+	SourceLine     *int    `json:"source_line" db:"sourceline"`
+	PendingRestart bool    `json:"pending_restart" db:"pending_restart"`
+	Changed        bool    `json:"changed" db:"-"`
+}
+
+// SettingsCategory contains settings of a single category
+type SettingsCategory struct {
+	Name     string    `json:"name"`
+	Settings []Setting `json:"settings"`
+}
+
+// SettingValue is the value of a setting on one side of the comparison
+type SettingValue struct {
+	Setting string `json:"setting"`
+	Unit    string `json:"unit"`
+	Source  string `json:"source"`
+}
+
+// SettingDiff is a setting with different values or sources on the compared servers.
+// The side is nil when the setting is not available on that server version.
+type SettingDiff struct {
+	Name     string        `json:"name"`
+	Category string        `json:"category"`
+	Left     *SettingValue `json:"left"`
+	Right    *SettingValue `json:"right"`
+}
+
+// SettingsOptions contains filters of the settings explorer
+type SettingsOptions struct {
+	Category       string // Only settings of the category
+	Source         string // Only settings with the source type
+	Changed        bool   // Only settings changed from the default value
+	PendingRestart bool   // Only settings waiting for a server restart
+}
+
+// Validate checks the settings filters
+func (opts *SettingsOptions) Validate() error {
+	if opts.Source != "" && !settingSources[opts.Source] {
+		return fmt.Errorf("invalid setting source: %v", opts.Source)
+	}
+	return nil
+}
+
+// settingSourceType classifies the pg_settings source. Values set with ALTER SYSTEM
+// are reported as configuration file values coming from postgresql.auto.conf.
+func settingSourceType(setting Setting) string {
+	switch setting.Source {
+	case "default":
+		return SettingSourceDefault
+	case "configuration file":
+		if setting.SourceFile != nil && filepath.Base(*setting.SourceFile) == autoConfFile {
+			return SettingSourceAlterSystem
+		}
+		return SettingSourceConfigFile
+	case "database", "user", "database user":
+		return SettingSourceDatabase
+	case "session", "client", "interactive":
+		return SettingSourceSession
+	default:
+		return SettingSourceOther
+	}
+}
+
+// settingChanged returns true when the current value differs from the built-in default.
+// Values adjusted by the server itself (override source) are not considered changed.
+func settingChanged(setting Setting) bool {
+	if setting.Source == "default" || setting.Source == "override" {
+		return false
+	}
+	return setting.BootValue == nil || *setting.BootValue != setting.Setting
+}
+
+// groupSettings classifies the settings and groups the ones matching the filters by category
+func groupSettings(settings []Setting, opts SettingsOptions) []SettingsCategory {
+	categories := []SettingsCategory{}
+
+	for _, setting := range settings {
+		setting.SourceType = settingSourceType(setting)
+		setting.Changed = settingChanged(setting)
+
+		if opts.Category != "" && setting.Category != opts.Category {
+			continue
+		}
+		if opts.Source != "" && setting.SourceType != opts.Source {
+			continue
+		}
+		if opts.Changed && !setting.Changed {
+			continue
+		}
+		if opts.PendingRestart && !setting.PendingRestart {
+			continue
+		}
+
+		n := len(categories)
+		if n == 0 || categories[n-1].Name != setting.Category {
+			categories = append(categories, SettingsCategory{Name: setting.Category, Settings: []Setting{}})
+			n++
+		}
+		categories[n-1].Settings = append(categories[n-1].Settings, setting)
+	}
+
+	return categories
+}
+
+// Settings returns server settings grouped by category
+func (client *Client) Settings(opts SettingsOptions) ([]SettingsCategory, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return nil, errors.New("settings explorer is not supported on CockroachDB")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	settings := []Setting{}
+	query := versionedStatement(statements.SettingsExplorer, getMajorMinorVersionString(client.serverVersion))
+	if err := client.db.SelectContext(ctx, &settings, query); err != nil {
+		return nil, err
+	}
+
+	return groupSettings(settings, opts), nil
+}
+
+// CompareSettings returns settings with different values, units or sources in two
+// ServerSettings results, including settings present only on one side
+func CompareSettings(left *Result, right *Result) ([]SettingDiff, error) {
+	leftValues, leftCategories, err := settingValues(left)
+	if err != nil {
+		return nil, err
+	}
+	rightValues, rightCategories, err := settingValues(right)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []SettingDiff{}
+
+	for name, leftValue := range leftValues {
+		rightValue, ok := rightValues[name]
+		if ok && *leftValue == *rightValue {
+			continue
+		}
+
+		diff := SettingDiff{Name: name, Category: leftCategories[name], Left: leftValue}
+		if ok {
+			diff.Right = rightValue
+		}
+		diffs = append(diffs, diff)
+	}
+
+	for name, rightValue := range rightValues {
+		if _, ok := leftValues[name]; !ok {
+			diffs = append(diffs, SettingDiff{Name: name, Category: rightCategories[name], Right: rightValue})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Category != diffs[j].Category {
+			return diffs[i].Category < diffs[j].Category
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+
+	return diffs, nil
+}
+
+// settingValues indexes values and categories of the ServerSettings result by setting name
+func settingValues(result *Result) (map[string]*SettingValue, map[string]string, error) {
+	columns := map[string]int{}
+	for i, name := range result.Columns {
+		columns[name] = i
+	}
+	for _, name := range []string{"name", "setting", "unit", "category", "source"} {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("settings column is missing: %v", name)
+		}
+	}
+
+	str := func(val interface{}) string {
+		if val == nil {
+			return ""
+		}
+		return fmt.Sprint(val)
+	}
+
+	values := map[string]*SettingValue{}
+	categories := map[string]string{}
+
+	for _, row := range result.Rows {
+		name := str(row[columns["name"]])
+		values[name] = &SettingValue{
+			Setting: str(row[columns["setting"]]),
+			Unit:    str(row[columns["unit"]]),
+			Source:  str(row[columns["source"]]),
+		}
+		categories[name] = str(row[columns["category"]])
+	}
+
+	return values, categories, nil
+}
+
+// listSettings are settings whose elements are quoted as identifiers by ALTER SYSTEM
+// (GUC_LIST_QUOTE), so every element has to be passed as a separate literal
+var listSettings = map[string]bool{
+	"search_path":               true,
+	"temp_tablespaces":          true,
+	"shared_preload_libraries":  true,
+	"session_preload_libraries": true,
+	"local_preload_libraries":   true,
+	"oauth_validator_libraries": true,
+}
+
+// splitListSetting splits the comma separated value into elements, double quoted
+// elements like "$user" are unquoted and could contain commas
+func splitListSetting(value string) []string {
+	elements := []string{}
+	current := strings.Builder{}
+	quoted := false
+
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case ch == '"' && quoted && i+1 < len(value) && value[i+1] == '"':
+			current.WriteByte('"')
+			i++
+		case ch == '"':
+			quoted = !quoted
+		case ch == ',' && !quoted:
+			elements = append(elements, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	elements = append(elements, strings.TrimSpace(current.String()))
+
+	return elements
+}
+
+// alterSystemQuery returns the ALTER SYSTEM statement. Values of list settings are
+// passed as separate literals, otherwise the server would write them as a single element.
+func alterSystemQuery(name string, value *string) string {
+	if value == nil {
+		return "ALTER SYSTEM RESET " + pq.QuoteIdentifier(name)
+	}
+
+	literals := []string{pq.QuoteLiteral(*value)}
+	if listSettings[name] && strings.TrimSpace(*value) != "" {
+		literals = literals[:0]
+		for _, element := range splitListSetting(*value) {
+			literals = append(literals, pq.QuoteLiteral(element))
+		}
+	}
+
+	return "ALTER SYSTEM SET " + pq.QuoteIdentifier(name) + " = " + strings.Join(literals, ", ")
+}
+
+// AlterSystemSet writes the setting value into postgresql.auto.conf. The value
+// is applied after the configuration reload or the server restart.
+func (client *Client) AlterSystemSet(name string, value string) error {
+	return client.alterSystem(name, &value)
+}
+
+// AlterSystemReset removes the setting from postgresql.auto.conf
+func (client *Client) AlterSystemReset(name string) error {
+	return client.alterSystem(name, nil)
+}
+
+func (client *Client) alterSystem(name string, value *string) error {
+	if err := client.checkSettingsChange(); err != nil {
+		return err
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return errors.New("setting name is required")
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	var settingContext string
+	err := client.db.QueryRowContext(ctx, "SELECT context FROM pg_catalog.pg_settings WHERE name = $1", name).Scan(&settingContext)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("unknown setting: %v", name)
+		}
+		return err
+	}
+	if settingContext == "internal" {
+		return fmt.Errorf("setting %v can't be changed", name)
+	}
+	if value != nil && listSettings[name] && strings.TrimSpace(*value) != "" {
+		for _, element := range splitListSetting(*value) {
+			if element == "" {
+				return fmt.Errorf("setting %v contains an empty list element", name)
+			}
+		}
+	}
+
+	// ALTER SYSTEM can't run inside a transaction block
+	_, err = client.db.ExecContext(ctx, alterSystemQuery(name, value))
+	return err
+}
+
+// ReloadConf signals the server to reload configuration files
+func (client *Client) ReloadConf() error {
+	if err := client.checkSettingsChange(); err != nil {
+		return err
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	var ok bool
+	if err := client.db.QueryRowContext(ctx, "SELECT pg_catalog.pg_reload_conf()").Scan(&ok); err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("configuration reload failed")
+	}
+	return nil
+}
+
+func (client *Client) checkSettingsChange() error {
+	if client.db == nil {
+		return errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return errors.New("settings change is not supported on CockroachDB")
+	}
+	if client.IsReadOnly() {
+		return errors.New("settings change is not allowed in read-only mode")
+	}
+	if major, minor := getMajorMinorVersion(client.serverVersion); major < 9 || (major == 9 && minor < 4) {
+		return fmt.Errorf("ALTER SYSTEM is not supported on PostgreSQL %v", client.serverVersion)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettingSourceType(t *testing.T) {
+	autoConf := "/var/lib/postgresql/data/postgresql.auto.conf"
+	conf := "/etc/postgresql/postgresql.conf"
+
+	examples := []struct {
+		setting Setting
+		source  string
+	}{
+		{setting: Setting{Source: "default"}, source: SettingSourceDefault},
+		{setting: Setting{Source: "configuration file", SourceFile: &conf}, source: SettingSourceConfigFile},
+		{setting: Setting{Source: "configuration file"}, source: SettingSourceConfigFile},
+		{setting: Setting{Source: "configuration file", SourceFile: &autoConf}, source: SettingSourceAlterSystem},
+		{setting: Setting{Source: "database user"}, source: SettingSourceDatabase},
+		{setting: Setting{Source: "client"}, source: SettingSourceSession},
+		{setting: Setting{Source: "session"}, source: SettingSourceSession},
+		{setting: Setting{Source: "command line"}, source: SettingSourceOther},
+		{setting: Setting{Source: "override"}, source: SettingSourceOther},
+	}
+
+	for _, ex := range examples {
+		assert.Equal(t, ex.source, settingSourceType(ex.setting), ex.setting.Source)
+	}
+}
+
+func TestGroupSettings(t *testing.T) {
+	boot := func(val string) *string { return &val }
+
+	settings := []Setting{
+		{Name: "autovacuum", Category: "Autovacuum", Setting: "on", BootValue: boot("on"), Source: "default"},
+		{Name: "autovacuum_naptime", Category: "Autovacuum", Setting: "30", BootValue: boot("60"), Source: "configuration file"},
+		{Name: "shared_buffers", Category: "Resource Usage / Memory", Setting: "16384", BootValue: boot("1024"), Source: "configuration file", PendingRestart: true},
+		{Name: "wal_buffers", Category: "Write-Ahead Log / Settings", Setting: "512", BootValue: boot("-1"), Source: "override"},
+		{Name: "work_mem", Category: "Resource Usage / Memory", Setting: "4096", BootValue: boot("4096"), Source: "session"},
+	}
+
+	categories := groupSettings(settings, SettingsOptions{})
+	assert.Equal(t, 4, len(categories))
+	assert.Equal(t, "Autovacuum", categories[0].Name)
+	assert.Equal(t, 2, len(categories[0].Settings))
+	assert.Equal(t, SettingSourceConfigFile, categories[0].Settings[1].SourceType)
+	assert.True(t, categories[0].Settings[1].Changed)
+	assert.False(t, categories[3].Settings[0].Changed)
+
+	categories = groupSettings(settings, SettingsOptions{Changed: true})
+	assert.Equal(t, 2, len(categories))
+	assert.Equal(t, "autovacuum_naptime", categories[0].Settings[0].Name)
+	assert.Equal(t, "shared_buffers", categories[1].Settings[0].Name)
+
+	categories = groupSettings(settings, SettingsOptions{PendingRestart: true})
+	assert.Equal(t, 1, len(categories))
+	assert.Equal(t, "shared_buffers", categories[0].Settings[0].Name)
+
+	categories = groupSettings(settings, SettingsOptions{Category: "Resource Usage / Memory", Source: SettingSourceSession})
+	assert.Equal(t, 1, len(categories))
+	assert.Equal(t, "work_mem", categories[0].Settings[0].Name)
+
+	assert.Empty(t, groupSettings(settings, SettingsOptions{Category: "Missing"}))
+}
+
+func TestSettingsOptionsValidate(t *testing.T) {
+	assert.NoError(t, (&SettingsOptions{}).Validate())
+	assert.NoError(t, (&SettingsOptions{Source: SettingSourceAlterSystem}).Validate())
+	assert.EqualError(t, (&SettingsOptions{Source: "file"}).Validate(), "invalid setting source: file")
+}
+
+func TestAlterSystemQuery(t *testing.T) {
+	value := "256MB"
+	assert.Equal(t, `ALTER SYSTEM SET "work_mem" = '256MB'`, alterSystemQuery("work_mem", &value))
+
+	value = "it's"
+	assert.Equal(t, `ALTER SYSTEM SET "application_name" = 'it''s'`, alterSystemQuery("application_name", &value))
+
+	assert.Equal(t, `ALTER SYSTEM RESET "work_mem"`, alterSystemQuery("work_mem", nil))
+
+	value = "pg_stat_statements,auto_explain"
+	assert.Equal(t,
+		`ALTER SYSTEM SET "shared_preload_libraries" = 'pg_stat_statements', 'auto_explain'`,
+		alterSystemQuery("shared_preload_libraries", &value),
+	)
+
+	value = `"$user", public, "my,schema"`
+	assert.Equal(t,
+		`ALTER SYSTEM SET "search_path" = '$user', 'public', 'my,schema'`,
+		alterSystemQuery("search_path", &value),
+	)
+
+	value = ""
+	assert.Equal(t, `ALTER SYSTEM SET "temp_tablespaces" = ''`, alterSystemQuery("temp_tablespaces", &value))
+}
+
+func TestSplitListSetting(t *testing.T) {
+	assert.Equal(t, []string{"a"}, splitListSetting("a"))
+	assert.Equal(t, []string{"a", "b"}, splitListSetting(" a , b "))
+	assert.Equal(t, []string{"a", "", "b"}, splitListSetting("a,,b"))
+	assert.Equal(t, []string{`say "hi"`, "b"}, splitListSetting(`"say ""hi""", b`))
+}
+
+func TestCompareSettings(t *testing.T) {
+	columns := []string{"name", "setting", "unit", "category", "source"}
+
+	left := &Result{
+		Columns: columns,
+		Rows: []Row{
+			{"work_mem", "4096", "kB", "Resource Usage / Memory", "default"},
+			{"shared_buffers", "16384", "8kB", "Resource Usage / Memory", "configuration file"},
+			{"jit", "on", nil, "Query Tuning / Other Planner Options", "default"},
+			{"vacuum_defer_cleanup_age", "0", nil, "Replication / Primary Server", "default"},
+		},
+	}
+	right := &Result{
+		Columns: columns,
+		Rows: []Row{
+			{"work_mem", "4096", "kB", "Resource Usage / Memory", "default"},
+			{"shared_buffers", "16384", "8kB", "Resource Usage / Memory", "default"},
+			{"jit", "off", nil, "Query Tuning / Other Planner Options", "configuration file"},
+			{"io_method", "worker", nil, "Resource Usage / I/O", "default"},
+		},
+	}
+
+	diffs, err := CompareSettings(left, right)
+	assert.NoError(t, err)
+	assert.Equal(t, []SettingDiff{
+		{
+			Name:     "jit",
+			Category: "Query Tuning / Other Planner Options",
+			Left:     &SettingValue{Setting: "on", Source: "default"},
+			Right:    &SettingValue{Setting: "off", Source: "configuration file"},
+		},
+		{
+			Name:     "vacuum_defer_cleanup_age",
+			Category: "Replication / Primary Server",
+			Left:     &SettingValue{Setting: "0", Source: "default"},
+		},
+		{
+			Name:     "io_method",
+			Category: "Resource Usage / I/O",
+			Right:    &SettingValue{Setting: "worker", Source: "default"},
+		},
+		{
+			Name:     "shared_buffers",
+			Category: "Resource Usage / Memory",
+			Left:     &SettingValue{Setting: "16384", Unit: "8kB", Source: "configuration file"},
+			Right:    &SettingValue{Setting: "16384", Unit: "8kB", Source: "default"},
+		},
+	}, diffs)
+
+	diffs, err = CompareSettings(left, left)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	_, err = CompareSettings(left, &Result{Columns: []string{"name", "setting"}})
+	assert.EqualError(t, err, "settings column is missing: unit")
+}
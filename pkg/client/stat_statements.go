@@ -0,0 +1,177 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const (
+	StatementsSortTotalTime = "total_time"
+	StatementsSortMeanTime  = "mean_time"
+	StatementsSortCalls     = "calls"
+	StatementsSortRows      = "rows"
+	StatementsSortIO        = "io"
+
+	// Number of statements returned when the limit is not specified
+	defaultStatementsLimit = 50
+	maxStatementsLimit     = 1000
+)
+
+var (
+	errStatStatementsMissing = errors.New("pg_stat_statements extension is not installed")
+
+	// Result columns used for sorting
+	statementsSortColumns = map[string]string{
+		StatementsSortTotalTime: "total_time",
+		StatementsSortMeanTime:  "mean_time",
+		StatementsSortCalls:     "calls",
+		StatementsSortRows:      "rows",
+		StatementsSortIO:        "io_blocks",
+	}
+)
+
+// StatementsOptions contains parameters of the top statements report
+type StatementsOptions struct {
+	SortBy string // Sort key: total_time, mean_time, calls, rows or io
+	Limit  int    // Number of statements to return
+}
+
+// Validate checks the report options and fills in defaults
+func (opts *StatementsOptions) Validate() error {
+	if opts.SortBy == "" {
+		opts.SortBy = StatementsSortTotalTime
+	}
+	if _, ok := statementsSortColumns[opts.SortBy]; !ok {
+		return fmt.Errorf("invalid sort key: %v", opts.SortBy)
+	}
+
+	if opts.Limit < 0 {
+		return errors.New("limit must be greater than 0")
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultStatementsLimit
+	}
+	if opts.Limit > maxStatementsLimit {
+		opts.Limit = maxStatementsLimit
+	}
+
+	return nil
+}
+
+// StatStatements returns the top statements of the current database collected by pg_stat_statements
+func (client *Client) StatStatements(opts StatementsOptions) (*Result, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	schema, columns, err := client.statStatementsColumns()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.query(statStatementsQuery(schema, columns, opts))
+}
+
+// ResetStatStatements discards statistics collected by pg_stat_statements
+func (client *Client) ResetStatStatements() error {
+	schema, _, err := client.statStatementsColumns()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := client.context()
+	defer cancel()
+
+	_, err = client.db.ExecContext(ctx, fmt.Sprintf("SELECT %s.pg_stat_statements_reset()", pq.QuoteIdentifier(schema)))
+	return err
+}
+
+// statStatementsColumns returns the extension schema and the list of view columns,
+// which differ between extension versions
+func (client *Client) statStatementsColumns() (string, map[string]bool, error) {
+	if client.db == nil {
+		return "", nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return "", nil, errors.New("pg_stat_statements is not supported on CockroachDB")
+	}
+
+	schemas, err := client.fetchRows(`
+		SELECT n.nspname::text
+		FROM pg_extension e
+		JOIN pg_namespace n ON n.oid = e.extnamespace
+		WHERE e.extname = 'pg_stat_statements'`,
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(schemas) == 0 {
+		return "", nil, errStatStatementsMissing
+	}
+
+	names, err := client.fetchRows(`
+		SELECT a.attname::text
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = 'pg_stat_statements' AND a.attnum > 0 AND NOT a.attisdropped`,
+		schemas[0],
+	)
+	if err != nil {
+		return "", nil, err
+	}
+
+	columns := map[string]bool{}
+	for _, name := range names {
+		columns[name] = true
+	}
+
+	return schemas[0], columns, nil
+}
+
+// statStatementsQuery builds the report query for the available view columns:
+// total_time and mean_time were renamed to total_exec_time and mean_exec_time in 1.8,
+// blk_read_time and blk_write_time to shared_blk_read_time and shared_blk_write_time in 1.11.
+func statStatementsQuery(schema string, columns map[string]bool, opts StatementsOptions) string {
+	pick := func(exprs ...string) string {
+		for i := 0; i < len(exprs)-1; i += 2 {
+			if columns[exprs[i]] {
+				return exprs[i+1]
+			}
+		}
+		return exprs[len(exprs)-1]
+	}
+
+	queryID := pick("queryid", "s.queryid", "NULL::bigint")
+	totalTime := pick("total_exec_time", "s.total_exec_time", "total_time", "s.total_time", "NULL::float8")
+	meanTime := pick("mean_exec_time", "s.mean_exec_time", "mean_time", "s.mean_time", "s.total_time / NULLIF(s.calls, 0)")
+	ioTime := pick(
+		"shared_blk_read_time", "s.shared_blk_read_time + s.shared_blk_write_time",
+		"blk_read_time", "s.blk_read_time + s.blk_write_time",
+		"NULL::float8",
+	)
+
+	return fmt.Sprintf(`SELECT
+  %s AS queryid,
+  r.rolname AS username,
+  s.query,
+  s.calls,
+  ROUND((%s)::numeric, 2) AS total_time,
+  ROUND((%s)::numeric, 2) AS mean_time,
+  s.rows,
+  s.shared_blks_hit,
+  s.shared_blks_read,
+  s.shared_blks_read + s.shared_blks_written + s.temp_blks_read + s.temp_blks_written AS io_blocks,
+  ROUND((%s)::numeric, 2) AS io_time,
+  ROUND(100.0 * s.shared_blks_hit / NULLIF(s.shared_blks_hit + s.shared_blks_read, 0), 2) AS cache_hit_ratio
+FROM %s.pg_stat_statements s
+LEFT JOIN pg_roles r ON r.oid = s.userid
+WHERE s.dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+ORDER BY %s DESC NULLS LAST
+LIMIT %d`,
+		queryID, totalTime, meanTime, ioTime,
+		pq.QuoteIdentifier(schema), statementsSortColumns[opts.SortBy], opts.Limit,
+	)
+}
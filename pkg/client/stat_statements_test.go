@@ -0,0 +1,68 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementsOptionsValidate(t *testing.T) {
+	opts := StatementsOptions{}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, StatementsOptions{SortBy: "total_time", Limit: 50}, opts)
+
+	opts = StatementsOptions{SortBy: "io", Limit: 5000}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, 1000, opts.Limit)
+
+	opts = StatementsOptions{SortBy: "query"}
+	assert.EqualError(t, opts.Validate(), "invalid sort key: query")
+
+	opts = StatementsOptions{Limit: -1}
+	assert.EqualError(t, opts.Validate(), "limit must be greater than 0")
+}
+
+func TestStatStatementsQuery(t *testing.T) {
+	opts := StatementsOptions{SortBy: "io", Limit: 10}
+
+	t.Run("legacy columns", func(t *testing.T) {
+		columns := map[string]bool{
+			"queryid":        true,
+			"total_time":     true,
+			"mean_time":      true,
+			"blk_read_time":  true,
+			"blk_write_time": true,
+		}
+
+		query := statStatementsQuery("public", columns, opts)
+		assert.Contains(t, query, "s.queryid AS queryid")
+		assert.Contains(t, query, "ROUND((s.total_time)::numeric, 2) AS total_time")
+		assert.Contains(t, query, "ROUND((s.mean_time)::numeric, 2) AS mean_time")
+		assert.Contains(t, query, "ROUND((s.blk_read_time + s.blk_write_time)::numeric, 2) AS io_time")
+		assert.Contains(t, query, `FROM "public".pg_stat_statements s`)
+		assert.Contains(t, query, "ORDER BY io_blocks DESC NULLS LAST\nLIMIT 10")
+	})
+
+	t.Run("current columns", func(t *testing.T) {
+		columns := map[string]bool{
+			"queryid":               true,
+			"total_exec_time":       true,
+			"mean_exec_time":        true,
+			"shared_blk_read_time":  true,
+			"shared_blk_write_time": true,
+		}
+
+		query := statStatementsQuery("ext", columns, StatementsOptions{SortBy: "mean_time", Limit: 5})
+		assert.Contains(t, query, "ROUND((s.total_exec_time)::numeric, 2) AS total_time")
+		assert.Contains(t, query, "ROUND((s.mean_exec_time)::numeric, 2) AS mean_time")
+		assert.Contains(t, query, "ROUND((s.shared_blk_read_time + s.shared_blk_write_time)::numeric, 2) AS io_time")
+		assert.Contains(t, query, "ORDER BY mean_time DESC NULLS LAST")
+	})
+
+	t.Run("oldest columns", func(t *testing.T) {
+		query := statStatementsQuery("public", map[string]bool{"total_time": true}, opts)
+		assert.Contains(t, query, "NULL::bigint AS queryid")
+		assert.Contains(t, query, "ROUND((s.total_time / NULLIF(s.calls, 0))::numeric, 2) AS mean_time")
+		assert.Contains(t, query, "ROUND((NULL::float8)::numeric, 2) AS io_time")
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/lib/pq"
 )
 
 var (
@@ -80,6 +82,12 @@ func checkVersionRequirement(client, server string) bool {
 	return clientMajor >= serverMajor
 }
 
+// quoteTable returns a safely quoted, schema-qualified table name
+func quoteTable(table string) string {
+	schema, name := getSchemaAndTable(table)
+	return pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(name)
+}
+
 // containsRestrictedKeywords returns true if given keyword is not allowed in read-only mode
 func containsRestrictedKeywords(str string) bool {
 	str = reSlashComment.ReplaceAllString(str, "")
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
 )
 
 func TestDetectServerType(t *testing.T) {
@@ -114,3 +116,38 @@ func TestCheckVersionRequirement(t *testing.T) {
 		assert.Equal(t, ex.result, checkVersionRequirement(ex.client, ex.server))
 	}
 }
+
+func TestStatementsAllowedInReadOnlyMode(t *testing.T) {
+	examples := map[string]string{
+		"IndexHealth":            statements.IndexHealth,
+		"BlockingLocksWaitstart": statements.BlockingLocksWaitstart,
+		"BloatEstimate":          statements.BloatEstimate,
+		"BloatPgstattuple":       statements.BloatPgstattuple,
+		"Maintenance":            statements.Maintenance,
+		"Privileges":             statements.Privileges,
+		"DefaultPrivileges":      statements.DefaultPrivileges,
+		"Dependencies":           statements.Dependencies,
+		"ColumnStats":            statements.ColumnStats,
+		"Search":                 statements.Search,
+		"ValueSearchColumns":     statements.ValueSearchColumns,
+		"TableSizes":             statements.TableSizes,
+	}
+
+	versioned := map[string]map[string]string{
+		"BlockingLocks":       statements.BlockingLocks,
+		"ReplicationStandbys": statements.ReplicationStandbys,
+		"ReplicationSlots":    statements.ReplicationSlots,
+		"ReplicationReceiver": statements.ReplicationReceiver,
+		"Roles":               statements.Roles,
+		"SettingsExplorer":    statements.SettingsExplorer,
+	}
+	for name, queries := range versioned {
+		for version, query := range queries {
+			examples[name+" "+version] = query
+		}
+	}
+
+	for name, query := range examples {
+		assert.False(t, containsRestrictedKeywords(query), name)
+	}
+}
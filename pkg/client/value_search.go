@@ -0,0 +1,356 @@
+package client
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/sosedoff/pgweb/pkg/statements"
+)
+
+const (
+	ValueMatchExact    = "exact"
+	ValueMatchContains = "contains"
+
+	defaultValueSearchTableTimeout = 5 * time.Second
+	defaultValueSearchLimit        = 100
+	maxValueSearchLimit            = 10000
+)
+
+var (
+	reUUID = regexp.MustCompile(`(?i)^[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}$`)
+
+	// Decimal numbers accepted by the numeric cast on every server version,
+	// unlike strconv.ParseFloat there is no Inf, NaN or hex notation
+	reDecimal = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+)
+
+// ValueSearchOptions contains parameters of the value search across tables
+type ValueSearchOptions struct {
+	Value         string        // Value to look for
+	Schemas       []string      // Schemas to search, all schemas when empty
+	Match         string        // exact or contains, contains only applies to text columns
+	TableTimeout  time.Duration // Statement timeout of every table scan
+	SamplePercent float64       // Percent of table pages to read, the whole table is read when 0 or 100
+	Limit         int           // Maximum number of hits
+}
+
+// Validate checks the options and fills in defaults
+func (opts *ValueSearchOptions) Validate() error {
+	if opts.Value == "" {
+		return errors.New("search value is required")
+	}
+	if opts.Match == "" {
+		opts.Match = ValueMatchExact
+	}
+	if opts.Match != ValueMatchExact && opts.Match != ValueMatchContains {
+		return fmt.Errorf("invalid match mode: %v", opts.Match)
+	}
+	if opts.TableTimeout == 0 {
+		opts.TableTimeout = defaultValueSearchTableTimeout
+	}
+	if opts.TableTimeout < 0 {
+		return errors.New("table timeout must be greater than 0")
+	}
+	if opts.SamplePercent == 0 {
+		opts.SamplePercent = 100
+	}
+	if opts.SamplePercent < 0 || opts.SamplePercent > 100 {
+		return fmt.Errorf("invalid sample percent: %v", opts.SamplePercent)
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultValueSearchLimit
+	}
+	if opts.Limit < 0 || opts.Limit > maxValueSearchLimit {
+		return fmt.Errorf("limit must be between 1 and %v", maxValueSearchLimit)
+	}
+	return nil
+}
+
+// ValueSearchHit is a row containing the value
+type ValueSearchHit struct {
+	Table   string            `json:"table"`
+	Columns []string          `json:"columns"` // Columns containing the value
+	RowKey  map[string]string `json:"row_key"` // Primary key values or ctid of the row
+}
+
+// ValueSearchSkip is a table that could not be searched
+type ValueSearchSkip struct {
+	Table string `json:"table"`
+	Error string `json:"error"`
+}
+
+// ValueSearchProgress is reported after each searched table
+type ValueSearchProgress struct {
+	Table string `json:"table"`
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+	Hits  int    `json:"hits"`
+}
+
+// ValueSearchResult contains hits of the value search
+type ValueSearchResult struct {
+	Hits      []ValueSearchHit  `json:"hits"`
+	Skipped   []ValueSearchSkip `json:"skipped"`
+	Tables    int               `json:"tables"`    // Number of tables with matching columns
+	Truncated bool              `json:"truncated"` // Limit of hits was reached
+}
+
+type valueSearchColumn struct {
+	Schema       string `db:"schema_name"`
+	Table        string `db:"table_name"`
+	Column       string `db:"column_name"`
+	TypeName     string `db:"type_name"`
+	TypeCategory string `db:"type_category"`
+	PrimaryKey   bool   `db:"primary_key"`
+}
+
+type valueSearchTable struct {
+	Schema      string
+	Name        string
+	Columns     []valueSearchMatch // Columns that could contain the value
+	PrimaryKeys []string
+}
+
+// valueSearchMatch describes how the value is compared with the column
+type valueSearchMatch struct {
+	Column string
+	Cast   string // Type of the value in the comparison
+	Like   bool   // Match with ILIKE pattern
+}
+
+func (t valueSearchTable) String() string {
+	return t.Schema + "." + t.Name
+}
+
+// valueSearchColumnMatch returns how the value is matched against the column,
+// false means the column type can't hold the value
+func valueSearchColumnMatch(column valueSearchColumn, value string, match string) (valueSearchMatch, bool) {
+	result := valueSearchMatch{Column: column.Column}
+
+	// Text columns
+	if column.TypeCategory == "S" {
+		result.Cast = "text"
+		result.Like = match == ValueMatchContains
+		return result, true
+	}
+
+	switch column.TypeName {
+	case "uuid":
+		result.Cast = "uuid"
+		return result, reUUID.MatchString(value)
+	case "int2", "int4", "int8":
+		result.Cast = "bigint"
+		_, err := strconv.ParseInt(value, 10, 64)
+		return result, err == nil
+	case "numeric", "float4", "float8":
+		result.Cast = "numeric"
+		return result, reDecimal.MatchString(value)
+	}
+
+	return result, false
+}
+
+// valueSearchTables groups columns by table and keeps tables with columns that could contain the value
+func valueSearchTables(columns []valueSearchColumn, value string, match string) []valueSearchTable {
+	tables := []valueSearchTable{}
+
+	var current *valueSearchTable
+	for _, column := range columns {
+		if current == nil || current.Schema != column.Schema || current.Name != column.Table {
+			if current != nil && len(current.Columns) > 0 {
+				tables = append(tables, *current)
+			}
+			current = &valueSearchTable{Schema: column.Schema, Name: column.Table}
+		}
+
+		if column.PrimaryKey {
+			current.PrimaryKeys = append(current.PrimaryKeys, column.Column)
+		}
+		if m, ok := valueSearchColumnMatch(column, value, match); ok {
+			current.Columns = append(current.Columns, m)
+		}
+	}
+	if current != nil && len(current.Columns) > 0 {
+		tables = append(tables, *current)
+	}
+
+	return tables
+}
+
+// valueSearchQuery returns the query selecting row keys and matched column flags
+func valueSearchQuery(table valueSearchTable, opts ValueSearchOptions, limit int) (string, []interface{}) {
+	args := []interface{}{}
+	valueParam, patternParam := "", ""
+
+	for _, m := range table.Columns {
+		if m.Like && patternParam == "" {
+			args = append(args, "%"+escapeLike(opts.Value)+"%")
+			patternParam = fmt.Sprintf("$%d", len(args))
+		}
+		if !m.Like && valueParam == "" {
+			args = append(args, opts.Value)
+			valueParam = fmt.Sprintf("$%d", len(args))
+		}
+	}
+
+	conditions := []string{}
+	for _, m := range table.Columns {
+		if m.Like {
+			conditions = append(conditions, pq.QuoteIdentifier(m.Column)+" ILIKE "+patternParam)
+		} else {
+			conditions = append(conditions, pq.QuoteIdentifier(m.Column)+" = "+valueParam+"::"+m.Cast)
+		}
+	}
+
+	fields := []string{}
+	if len(table.PrimaryKeys) > 0 {
+		for _, key := range table.PrimaryKeys {
+			fields = append(fields, pq.QuoteIdentifier(key)+"::text")
+		}
+	} else {
+		fields = append(fields, "ctid::text")
+	}
+	for _, condition := range conditions {
+		fields = append(fields, "("+condition+")")
+	}
+
+	source := pq.QuoteIdentifier(table.Schema) + "." + pq.QuoteIdentifier(table.Name)
+	if opts.SamplePercent < 100 {
+		source += " TABLESAMPLE SYSTEM (" + strconv.FormatFloat(opts.SamplePercent, 'f', -1, 64) + ")"
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s LIMIT %d",
+		strings.Join(fields, ", "),
+		source,
+		strings.Join(conditions, " OR "),
+		limit,
+	)
+
+	return query, args
+}
+
+// SearchValue scans columns of all tables in the schemas for the value. Text columns
+// are always searched, uuid and numeric columns only when the value has their format.
+// Tables that fail or exceed the time limit are reported as skipped.
+func (client *Client) SearchValue(ctx context.Context, opts ValueSearchOptions, progress func(ValueSearchProgress)) (*ValueSearchResult, error) {
+	if client.db == nil {
+		return nil, errNotConnected
+	}
+	if client.serverType == cockroachType {
+		return nil, errors.New("value search is not supported on CockroachDB")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if major, minor := getMajorMinorVersion(client.serverVersion); opts.SamplePercent < 100 && (major < 9 || (major == 9 && minor < 5)) {
+		return nil, fmt.Errorf("table sampling is not supported on PostgreSQL %v", client.serverVersion)
+	}
+
+	defer func() {
+		client.lastQueryTime = time.Now().UTC()
+	}()
+
+	var schemas interface{}
+	if len(opts.Schemas) > 0 {
+		schemas = pq.Array(opts.Schemas)
+	}
+
+	columns := []valueSearchColumn{}
+	if err := client.db.SelectContext(ctx, &columns, statements.ValueSearchColumns, schemas); err != nil {
+		return nil, err
+	}
+
+	tables := valueSearchTables(columns, opts.Value, opts.Match)
+	result := &ValueSearchResult{
+		Hits:    []ValueSearchHit{},
+		Skipped: []ValueSearchSkip{},
+		Tables:  len(tables),
+	}
+
+	for i, table := range tables {
+		if len(result.Hits) >= opts.Limit {
+			result.Truncated = true
+			break
+		}
+
+		hits, err := client.searchTableValue(ctx, table, opts, opts.Limit-len(result.Hits))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.Skipped = append(result.Skipped, ValueSearchSkip{Table: table.String(), Error: err.Error()})
+		}
+		result.Hits = append(result.Hits, hits...)
+
+		if progress != nil {
+			progress(ValueSearchProgress{Table: table.String(), Done: i + 1, Total: len(tables), Hits: len(result.Hits)})
+		}
+	}
+
+	return result, nil
+}
+
+// searchTableValue scans a single table in a read-only transaction limited by the statement timeout
+func (client *Client) searchTableValue(ctx context.Context, table valueSearchTable, opts ValueSearchOptions, limit int) ([]ValueSearchHit, error) {
+	tx, err := client.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint
+
+	timeout := fmt.Sprintf("SET LOCAL statement_timeout = %d", opts.TableTimeout.Milliseconds())
+	if _, err := tx.ExecContext(ctx, timeout); err != nil {
+		return nil, err
+	}
+
+	query, args := valueSearchQuery(table, opts, limit)
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := table.PrimaryKeys
+	if len(keys) == 0 {
+		keys = []string{"ctid"}
+	}
+
+	hits := []ValueSearchHit{}
+	for rows.Next() {
+		keyValues := make([]sql.NullString, len(keys))
+		matches := make([]sql.NullBool, len(table.Columns))
+
+		dest := []interface{}{}
+		for i := range keyValues {
+			dest = append(dest, &keyValues[i])
+		}
+		for i := range matches {
+			dest = append(dest, &matches[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return hits, err
+		}
+
+		hit := ValueSearchHit{Table: table.String(), Columns: []string{}, RowKey: map[string]string{}}
+		for i, key := range keys {
+			hit.RowKey[key] = keyValues[i].String
+		}
+		for i, m := range table.Columns {
+			if matches[i].Bool {
+				hit.Columns = append(hit.Columns, m.Column)
+			}
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits, rows.Err()
+}
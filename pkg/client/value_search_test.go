@@ -0,0 +1,90 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueSearchOptionsValidate(t *testing.T) {
+	opts := ValueSearchOptions{Value: "john@example.com"}
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, ValueMatchExact, opts.Match)
+	assert.Equal(t, 5*time.Second, opts.TableTimeout)
+	assert.Equal(t, float64(100), opts.SamplePercent)
+	assert.Equal(t, 100, opts.Limit)
+
+	assert.EqualError(t, (&ValueSearchOptions{}).Validate(), "search value is required")
+	assert.EqualError(t, (&ValueSearchOptions{Value: "a", Match: "regex"}).Validate(), "invalid match mode: regex")
+	assert.EqualError(t, (&ValueSearchOptions{Value: "a", SamplePercent: 101}).Validate(), "invalid sample percent: 101")
+	assert.EqualError(t, (&ValueSearchOptions{Value: "a", Limit: 100000}).Validate(), "limit must be between 1 and 10000")
+}
+
+func TestValueSearchTables(t *testing.T) {
+	columns := []valueSearchColumn{
+		{Schema: "public", Table: "events", Column: "id", TypeName: "int8", TypeCategory: "N", PrimaryKey: true},
+		{Schema: "public", Table: "events", Column: "payload", TypeName: "jsonb", TypeCategory: "U"},
+		{Schema: "public", Table: "users", Column: "id", TypeName: "uuid", TypeCategory: "U", PrimaryKey: true},
+		{Schema: "public", Table: "users", Column: "email", TypeName: "varchar", TypeCategory: "S"},
+		{Schema: "public", Table: "users", Column: "age", TypeName: "int4", TypeCategory: "N"},
+	}
+
+	tables := valueSearchTables(columns, "john", ValueMatchContains)
+	require.Equal(t, 1, len(tables))
+	assert.Equal(t, "public.users", tables[0].String())
+	assert.Equal(t, []string{"id"}, tables[0].PrimaryKeys)
+	assert.Equal(t, []valueSearchMatch{{Column: "email", Cast: "text", Like: true}}, tables[0].Columns)
+
+	tables = valueSearchTables(columns, "42", ValueMatchExact)
+	require.Equal(t, 2, len(tables))
+	assert.Equal(t, []valueSearchMatch{{Column: "id", Cast: "bigint"}}, tables[0].Columns)
+	assert.Equal(t, []valueSearchMatch{{Column: "email", Cast: "text"}, {Column: "age", Cast: "bigint"}}, tables[1].Columns)
+
+	tables = valueSearchTables(columns, "3F2504E0-4F89-11D3-9A0C-0305E82C3301", ValueMatchExact)
+	require.Equal(t, 1, len(tables))
+	assert.Equal(t, []valueSearchMatch{{Column: "id", Cast: "uuid"}, {Column: "email", Cast: "text"}}, tables[0].Columns)
+}
+
+func TestValueSearchColumnMatch(t *testing.T) {
+	column := valueSearchColumn{Column: "price", TypeName: "numeric", TypeCategory: "N"}
+
+	for _, value := range []string{"42", "-1.5", "+.5", "3.", "1e3", "2.5E-4"} {
+		m, ok := valueSearchColumnMatch(column, value, ValueMatchExact)
+		assert.True(t, ok, value)
+		assert.Equal(t, "numeric", m.Cast)
+	}
+
+	// Values rejected by the numeric cast would fail the whole table
+	for _, value := range []string{"Inf", "Infinity", "-inf", "NaN", "0x1p4", "1_000", " 42", "1e", "."} {
+		_, ok := valueSearchColumnMatch(column, value, ValueMatchExact)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestValueSearchQuery(t *testing.T) {
+	table := valueSearchTable{
+		Schema:      "public",
+		Name:        "users",
+		PrimaryKeys: []string{"id"},
+		Columns: []valueSearchMatch{
+			{Column: "email", Cast: "text", Like: true},
+			{Column: "age", Cast: "bigint"},
+		},
+	}
+
+	query, args := valueSearchQuery(table, ValueSearchOptions{Value: "4_2", SamplePercent: 100}, 10)
+	assert.Equal(t, `SELECT "id"::text, ("email" ILIKE $1), ("age" = $2::bigint) FROM "public"."users" WHERE "email" ILIKE $1 OR "age" = $2::bigint LIMIT 10`, query)
+	assert.Equal(t, []interface{}{`%4\_2%`, "4_2"}, args)
+
+	table = valueSearchTable{
+		Schema:  "public",
+		Name:    "notes",
+		Columns: []valueSearchMatch{{Column: "body", Cast: "text"}},
+	}
+
+	query, args = valueSearchQuery(table, ValueSearchOptions{Value: "hello", SamplePercent: 5}, 3)
+	assert.Equal(t, `SELECT ctid::text, ("body" = $1::text) FROM "public"."notes" TABLESAMPLE SYSTEM (5) WHERE "body" = $1::text LIMIT 3`, query)
+	assert.Equal(t, []interface{}{"hello"}, args)
+}
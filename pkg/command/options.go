@@ -63,12 +63,17 @@ type Options struct {
 	// 仅支持从书签创建连接
 	BookmarksOnly bool `long:"bookmarks-only" description:"Allow only connections from bookmarks"`
 	// 本地查询目录
-	QueriesDir        string `long:"queries-dir" description:"Overrides default directory for local queries"`
-	DisablePrettyJSON bool   `long:"no-pretty-json" description:"Disable JSON formatting feature for result export"`
-	DisableSSH        bool   `long:"no-ssh" description:"Disable database connections via SSH"`
-	ConnectBackend    string `long:"connect-backend" description:"Enable database authentication through a third party backend"`
-	ConnectToken      string `long:"connect-token" description:"Authentication token for the third-party connect backend"`
-	ConnectHeaders    string `long:"connect-headers" description:"List of headers to pass to the connect backend"`
+	QueriesDir          string `long:"queries-dir" description:"Overrides default directory for local queries"`
+	DisablePrettyJSON   bool   `long:"no-pretty-json" description:"Disable JSON formatting feature for result export"`
+	DisableSSH          bool   `long:"no-ssh" description:"Disable database connections via SSH"`
+	DisableImport       bool   `long:"no-import" description:"Disable data import into tables"`
+	AllowSignals        bool   `long:"allow-signals" description:"Allow cancelling and terminating database backends"`
+	AllowStatsReset     bool   `long:"allow-stats-reset" description:"Allow resetting of pg_stat_statements statistics"`
+	AllowSettingsChange bool   `long:"allow-settings-change" description:"Allow changing server settings with ALTER SYSTEM and reloading configuration"`
+	DumpBinPaths        string `long:"dump-bin-paths" description:"Comma-separated list of directories or pg_dump/pg_restore/psql binaries to choose from, globs allowed"`
+	ConnectBackend      string `long:"connect-backend" description:"Enable database authentication through a third party backend"`
+	ConnectToken        string `long:"connect-token" description:"Authentication token for the third-party connect backend"`
+	ConnectHeaders      string `long:"connect-headers" description:"List of headers to pass to the connect backend"`
 	// 禁用链接存储超时，则不再检测链接是否超时，应用于 session manager
 	DisableConnectionIdleTimeout bool `long:"no-idle-timeout" description:"Disable connection idle timeout"`
 	// 设置链接超时时间，默认 180m
@@ -85,6 +90,19 @@ type Options struct {
 	MetricsEnabled bool   `long:"metrics" description:"Enable Prometheus metrics endpoint"`
 	MetricsPath    string `long:"metrics-path" description:"Path prefix for Prometheus metrics endpoint" default:"/metrics"`
 	MetricsAddr    string `long:"metrics-addr" description:"Listen host and port for Prometheus metrics server"`
+	// 已结束后台任务及其结果的保留时间，默认 60m
+	JobsRetention int `long:"jobs-retention" description:"Set retention of finished background jobs and their results in minutes" default:"60"`
+	// 每个会话可同时运行的查询任务数
+	QueryJobsLimit int `long:"query-jobs-limit" description:"Set maximum number of running query jobs per session" default:"3"`
+	// 单个查询任务结果的大小上限，单位 MB
+	QueryJobsMaxSize int `long:"query-jobs-max-size" description:"Set maximum size of a single query job result in megabytes" default:"64"`
+	// 所有保留的查询任务结果的大小上限，单位 MB
+	QueryJobsMemory int `long:"query-jobs-memory" description:"Set maximum total size of retained query job results in megabytes" default:"512"`
+	// 定期采集书签数据库的表大小快照
+	Snapshots          bool   `long:"snapshots" description:"Enable periodic table size snapshots of bookmarked databases"`
+	SnapshotsDir       string `long:"snapshots-dir" description:"Overrides default directory for table size snapshots"`
+	SnapshotsInterval  int    `long:"snapshots-interval" description:"Set table size snapshots interval in minutes" default:"60"`
+	SnapshotsBookmarks string `long:"snapshots-bookmarks" description:"Comma-separated list of bookmarks to take table size snapshots of"`
 }
 
 var Opts Options
@@ -211,6 +229,28 @@ func ParseOptions(args []string) (Options, error) {
 		}
 	}
 
+	if opts.JobsRetention < 1 {
+		return opts, errors.New("--jobs-retention must be greater than 0")
+	}
+	if opts.QueryJobsLimit < 1 {
+		return opts, errors.New("--query-jobs-limit must be greater than 0")
+	}
+	if opts.QueryJobsMaxSize < 1 {
+		return opts, errors.New("--query-jobs-max-size must be greater than 0")
+	}
+	if opts.QueryJobsMemory < opts.QueryJobsMaxSize {
+		return opts, errors.New("--query-jobs-memory must not be less than --query-jobs-max-size")
+	}
+
+	if opts.Snapshots {
+		if opts.SnapshotsBookmarks == "" {
+			return opts, errors.New("--snapshots-bookmarks flag must be set")
+		}
+		if opts.SnapshotsInterval < 1 {
+			return opts, errors.New("--snapshots-interval must be greater than 0")
+		}
+	}
+
 	homePath, err := homedir.Dir()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[WARN] can't detect home dir: %v", err)
@@ -225,6 +265,10 @@ func ParseOptions(args []string) (Options, error) {
 		if opts.QueriesDir == "" {
 			opts.QueriesDir = filepath.Join(homePath, ".pgweb/queries")
 		}
+
+		if opts.SnapshotsDir == "" {
+			opts.SnapshotsDir = filepath.Join(homePath, ".pgweb/snapshots")
+		}
 	}
 
 	return opts, nil
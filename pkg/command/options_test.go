@@ -30,6 +30,13 @@ func TestParseOptions(t *testing.T) {
 		assert.Equal(t, "*", opts.CorsOrigin)
 		assert.Equal(t, "", opts.Passfile)
 		assert.Equal(t, filepath.Join(hdir, ".pgweb/bookmarks"), opts.BookmarksDir)
+		assert.Equal(t, 60, opts.JobsRetention)
+		assert.Equal(t, 3, opts.QueryJobsLimit)
+		assert.Equal(t, 64, opts.QueryJobsMaxSize)
+		assert.Equal(t, 512, opts.QueryJobsMemory)
+		assert.Equal(t, false, opts.Snapshots)
+		assert.Equal(t, 60, opts.SnapshotsInterval)
+		assert.Equal(t, filepath.Join(hdir, ".pgweb/snapshots"), opts.SnapshotsDir)
 	})
 
 	t.Run("sessions", func(t *testing.T) {
@@ -94,4 +101,38 @@ func TestParseOptions(t *testing.T) {
 		_, err = ParseOptions([]string{"--bookmarks-only", "--connect-backend", "test", "--sessions", "--connect-token", "token", "--url", "127.0.0.2"})
 		assert.EqualError(t, err, "--connect-backend not supported in bookmarks-only mode")
 	})
+
+	t.Run("jobs", func(t *testing.T) {
+		_, err := ParseOptions([]string{"--jobs-retention", "0"})
+		assert.EqualError(t, err, "--jobs-retention must be greater than 0")
+
+		_, err = ParseOptions([]string{"--query-jobs-limit", "0"})
+		assert.EqualError(t, err, "--query-jobs-limit must be greater than 0")
+
+		_, err = ParseOptions([]string{"--query-jobs-max-size", "0"})
+		assert.EqualError(t, err, "--query-jobs-max-size must be greater than 0")
+
+		_, err = ParseOptions([]string{"--query-jobs-max-size", "100", "--query-jobs-memory", "50"})
+		assert.EqualError(t, err, "--query-jobs-memory must not be less than --query-jobs-max-size")
+
+		opts, err := ParseOptions([]string{"--jobs-retention", "15", "--query-jobs-limit", "1", "--query-jobs-max-size", "10", "--query-jobs-memory", "100"})
+		assert.NoError(t, err)
+		assert.Equal(t, 15, opts.JobsRetention)
+		assert.Equal(t, 1, opts.QueryJobsLimit)
+		assert.Equal(t, 10, opts.QueryJobsMaxSize)
+		assert.Equal(t, 100, opts.QueryJobsMemory)
+	})
+
+	t.Run("snapshots", func(t *testing.T) {
+		_, err := ParseOptions([]string{"--snapshots"})
+		assert.EqualError(t, err, "--snapshots-bookmarks flag must be set")
+
+		_, err = ParseOptions([]string{"--snapshots", "--snapshots-bookmarks", "prod", "--snapshots-interval", "0"})
+		assert.EqualError(t, err, "--snapshots-interval must be greater than 0")
+
+		opts, err := ParseOptions([]string{"--snapshots", "--snapshots-bookmarks", "prod,staging", "--snapshots-dir", "/tmp/snapshots"})
+		assert.NoError(t, err)
+		assert.Equal(t, "prod,staging", opts.SnapshotsBookmarks)
+		assert.Equal(t, "/tmp/snapshots", opts.SnapshotsDir)
+	})
 }
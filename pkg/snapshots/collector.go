@@ -0,0 +1,74 @@
+package snapshots
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sosedoff/pgweb/pkg/bookmarks"
+	"github.com/sosedoff/pgweb/pkg/client"
+)
+
+// Collector periodically takes table size snapshots of the bookmarked databases
+type Collector struct {
+	store     *Store
+	manager   bookmarks.Manager
+	bookmarks []string
+	interval  time.Duration
+	logger    *logrus.Logger
+}
+
+func NewCollector(store *Store, manager bookmarks.Manager, bookmarks []string, interval time.Duration, logger *logrus.Logger) *Collector {
+	return &Collector{
+		store:     store,
+		manager:   manager,
+		bookmarks: bookmarks,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Collect takes a snapshot of every bookmark, failed bookmarks are logged and skipped
+func (c *Collector) Collect() {
+	for _, id := range c.bookmarks {
+		if err := c.collect(id); err != nil {
+			c.logger.WithField("bookmark", id).WithError(err).Error("table size snapshot failed")
+		}
+	}
+}
+
+func (c *Collector) collect(id string) error {
+	bookmark, err := c.manager.Get(id)
+	if err != nil {
+		return err
+	}
+
+	cl, err := client.NewFromBookmark(bookmark)
+	if err != nil {
+		return err
+	}
+	defer cl.Close()
+
+	sizes, err := cl.TableSizes()
+	if err != nil {
+		return err
+	}
+
+	c.logger.WithField("bookmark", id).WithField("tables", len(sizes)).Debug("table size snapshot taken")
+
+	return c.store.Append(Snapshot{
+		Bookmark: id,
+		Time:     time.Now().UTC(),
+		Tables:   sizes,
+	})
+}
+
+// Run takes snapshots right away and then at every interval
+func (c *Collector) Run() {
+	c.logger.WithField("interval", c.interval).WithField("bookmarks", c.bookmarks).Info("table size snapshots enabled")
+
+	c.Collect()
+	for range time.Tick(c.interval) {
+		c.Collect()
+	}
+}
@@ -0,0 +1,116 @@
+package snapshots
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sosedoff/pgweb/pkg/client"
+)
+
+// Snapshot contains sizes of all user tables of the bookmarked database at a point in time
+type Snapshot struct {
+	Bookmark string             `json:"bookmark"`
+	Time     time.Time          `json:"time"`
+	Tables   []client.TableSize `json:"tables"`
+}
+
+// Point is the table size in a single snapshot
+type Point struct {
+	Time          time.Time `json:"time"`
+	TableBytes    int64     `json:"table_bytes"`
+	IndexBytes    int64     `json:"index_bytes"`
+	TotalBytes    int64     `json:"total_bytes"`
+	EstimatedRows int64     `json:"estimated_rows"`
+}
+
+// TableGrowth is the change of the table size between its first and last snapshot
+type TableGrowth struct {
+	Table       string    `json:"table"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	TotalBytes  int64     `json:"total_bytes"` // Size in the last snapshot
+	BytesDelta  int64     `json:"bytes_delta"`
+	RowsDelta   int64     `json:"rows_delta"`
+	BytesPerDay float64   `json:"bytes_per_day"`
+}
+
+func tableName(size client.TableSize) string {
+	return size.Schema + "." + size.Name
+}
+
+// History returns sizes of the table (schema.name) in the snapshots ordered by time
+func History(snapshots []Snapshot, table string) []Point {
+	points := []Point{}
+
+	for _, snapshot := range snapshots {
+		for _, size := range snapshot.Tables {
+			if tableName(size) != table {
+				continue
+			}
+			points = append(points, Point{
+				Time:          snapshot.Time,
+				TableBytes:    size.TableBytes,
+				IndexBytes:    size.IndexBytes,
+				TotalBytes:    size.TotalBytes,
+				EstimatedRows: size.EstimatedRows,
+			})
+			break
+		}
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+
+	return points
+}
+
+// Growth returns the size change of every table found in the snapshots, sorted by
+// the number of bytes added. Tables seen in a single snapshot have no growth.
+func Growth(snapshots []Snapshot) []TableGrowth {
+	sorted := make([]Snapshot, len(snapshots))
+	copy(sorted, snapshots)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	first := map[string]Point{}
+	last := map[string]Point{}
+
+	for _, snapshot := range sorted {
+		for _, size := range snapshot.Tables {
+			point := Point{Time: snapshot.Time, TotalBytes: size.TotalBytes, EstimatedRows: size.EstimatedRows}
+			name := tableName(size)
+			if _, ok := first[name]; !ok {
+				first[name] = point
+			}
+			last[name] = point
+		}
+	}
+
+	result := []TableGrowth{}
+	for name, from := range first {
+		to := last[name]
+		growth := TableGrowth{
+			Table:      name,
+			From:       from.Time,
+			To:         to.Time,
+			TotalBytes: to.TotalBytes,
+			BytesDelta: to.TotalBytes - from.TotalBytes,
+			RowsDelta:  to.EstimatedRows - from.EstimatedRows,
+		}
+		if days := to.Time.Sub(from.Time).Hours() / 24; days > 0 {
+			growth.BytesPerDay = float64(growth.BytesDelta) / days
+		}
+		result = append(result, growth)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BytesDelta != result[j].BytesDelta {
+			return result[i].BytesDelta > result[j].BytesDelta
+		}
+		return result[i].Table < result[j].Table
+	})
+
+	return result
+}
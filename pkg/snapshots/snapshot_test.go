@@ -0,0 +1,62 @@
+package snapshots
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sosedoff/pgweb/pkg/client"
+)
+
+func testSnapshots() []Snapshot {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return []Snapshot{
+		{
+			Time: start.Add(48 * time.Hour),
+			Tables: []client.TableSize{
+				{Schema: "public", Name: "books", TableBytes: 3000, IndexBytes: 1000, TotalBytes: 4000, EstimatedRows: 300},
+				{Schema: "public", Name: "authors", TotalBytes: 1000, EstimatedRows: 10},
+				{Schema: "public", Name: "events", TotalBytes: 5000, EstimatedRows: 500},
+			},
+		},
+		{
+			Time: start,
+			Tables: []client.TableSize{
+				{Schema: "public", Name: "books", TableBytes: 1500, IndexBytes: 500, TotalBytes: 2000, EstimatedRows: 100},
+				{Schema: "public", Name: "authors", TotalBytes: 1000, EstimatedRows: 10},
+			},
+		},
+	}
+}
+
+func TestHistory(t *testing.T) {
+	points := History(testSnapshots(), "public.books")
+	assert.Equal(t, 2, len(points))
+	assert.Equal(t, int64(2000), points[0].TotalBytes)
+	assert.Equal(t, int64(500), points[0].IndexBytes)
+	assert.Equal(t, int64(4000), points[1].TotalBytes)
+	assert.True(t, points[0].Time.Before(points[1].Time))
+
+	assert.Empty(t, History(testSnapshots(), "public.missing"))
+}
+
+func TestGrowth(t *testing.T) {
+	growth := Growth(testSnapshots())
+	assert.Equal(t, 3, len(growth))
+
+	assert.Equal(t, "public.books", growth[0].Table)
+	assert.Equal(t, int64(2000), growth[0].BytesDelta)
+	assert.Equal(t, int64(200), growth[0].RowsDelta)
+	assert.Equal(t, int64(4000), growth[0].TotalBytes)
+	assert.Equal(t, float64(1000), growth[0].BytesPerDay)
+
+	// Tables with a single snapshot have no growth yet
+	assert.Equal(t, "public.authors", growth[1].Table)
+	assert.Equal(t, int64(0), growth[1].BytesDelta)
+	assert.Equal(t, "public.events", growth[2].Table)
+	assert.Equal(t, float64(0), growth[2].BytesPerDay)
+
+	assert.Empty(t, Growth(nil))
+}
@@ -0,0 +1,135 @@
+package snapshots
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const fileExt = ".jsonl"
+
+var ErrInvalidBookmark = errors.New("invalid bookmark name")
+
+// Store keeps snapshots of every bookmark in a separate JSON lines file
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// path returns the snapshots file of the bookmark, names with path separators are rejected
+func (s *Store) path(bookmark string) (string, error) {
+	if bookmark == "" || bookmark == "." || bookmark == ".." || strings.ContainsAny(bookmark, `/\`) {
+		return "", ErrInvalidBookmark
+	}
+	return filepath.Join(s.dir, bookmark+fileExt), nil
+}
+
+// Append writes the snapshot at the end of the bookmark file
+func (s *Store) Append(snapshot Snapshot) error {
+	path, err := s.path(snapshot.Bookmark)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns snapshots of the bookmark taken since the given time
+func (s *Store) Read(bookmark string, since time.Time) ([]Snapshot, error) {
+	path, err := s.path(bookmark)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots := []Snapshot{}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return snapshots, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	// Lines could be large for databases with many tables, so no bufio.Scanner here
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var snapshot Snapshot
+			// Skip a partially written line
+			if jsonErr := json.Unmarshal(line, &snapshot); jsonErr != nil {
+				fmt.Fprintf(os.Stderr, "[WARN] skipping invalid snapshot in %q: %v\n", path, jsonErr)
+			} else if !snapshot.Time.Before(since) {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshots, nil
+}
+
+// Bookmarks returns names of bookmarks with stored snapshots
+func (s *Store) Bookmarks() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fileExt {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), fileExt))
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
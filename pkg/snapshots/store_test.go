@@ -0,0 +1,78 @@
+package snapshots
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sosedoff/pgweb/pkg/client"
+)
+
+func TestStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	store := NewStore(dir)
+
+	bookmarks, err := store.Bookmarks()
+	assert.NoError(t, err)
+	assert.Empty(t, bookmarks)
+
+	snapshots, err := store.Read("prod", time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		err := store.Append(Snapshot{
+			Bookmark: "prod",
+			Time:     start.Add(time.Duration(i) * time.Hour),
+			Tables:   []client.TableSize{{Schema: "public", Name: "books", TotalBytes: int64(i * 100)}},
+		})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, store.Append(Snapshot{Bookmark: "staging", Time: start}))
+
+	bookmarks, err = store.Bookmarks()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prod", "staging"}, bookmarks)
+
+	snapshots, err = store.Read("prod", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(snapshots))
+	assert.Equal(t, int64(200), snapshots[2].Tables[0].TotalBytes)
+
+	snapshots, err = store.Read("prod", start.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(snapshots))
+	assert.Equal(t, start.Add(2*time.Hour), snapshots[0].Time)
+}
+
+func TestStoreSkipsInvalidLines(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	assert.NoError(t, store.Append(Snapshot{Bookmark: "prod", Time: time.Now()}))
+
+	f, err := os.OpenFile(filepath.Join(dir, "prod.jsonl"), os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(`{"bookmark":"prod","ti`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	snapshots, err := store.Read("prod", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(snapshots))
+}
+
+func TestStoreInvalidBookmark(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	for _, name := range []string{"", "..", "../prod", `a\b`} {
+		assert.Equal(t, ErrInvalidBookmark, store.Append(Snapshot{Bookmark: name}), name)
+
+		_, err := store.Read(name, time.Time{})
+		assert.Equal(t, ErrInvalidBookmark, err, name)
+	}
+}
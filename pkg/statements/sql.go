@@ -38,6 +38,9 @@ var (
 	//go:embed sql/table_info_cockroach.sql
 	TableInfoCockroach string
 
+	//go:embed sql/table_columns.sql
+	TableColumns string
+
 	//go:embed sql/table_schema.sql
 	TableSchema string
 
@@ -53,9 +56,133 @@ var (
 	//go:embed sql/function.sql
 	Function string
 
+	// 分区表的分区键及（嵌套的）分区，需要 10 及以上版本
+	//go:embed sql/partitioned_table.sql
+	PartitionedTable string
+
+	// 外部表的外部服务器及选项
+	//go:embed sql/foreign_table.sql
+	ForeignTable string
+
+	// 触发器定义
+	//go:embed sql/trigger.sql
+	Trigger string
+
+	// 自定义类型（枚举、域、复合类型及范围类型）定义
+	//go:embed sql/type.sql
+	Type string
+
+	// 已安装的扩展
+	//go:embed sql/extension.sql
+	Extension string
+
+	// 事件触发器列表，需要 9.3 及以上版本
+	//go:embed sql/event_triggers.sql
+	EventTriggers string
+
+	// 事件触发器定义
+	//go:embed sql/event_trigger.sql
+	EventTrigger string
+
 	//go:embed sql/settings.sql
 	Settings string
 
+	// 按分类查询服务器设置及其来源、默认值，需要 9.5 及以上版本
+	//go:embed sql/settings_explorer.sql
+	settingsExplorer string
+
+	// 按分类查询服务器设置，适用于 9.1 - 9.4 版本，不包含 pending_restart
+	//go:embed sql/settings_explorer_legacy.sql
+	settingsExplorerLegacy string
+
+	// 查询索引健康状况：未使用、重复、冗余、无效的索引以及缺少索引的外键
+	//go:embed sql/index_health.sql
+	IndexHealth string
+
+	// 根据统计信息估算表和 btree 索引的膨胀
+	//go:embed sql/bloat_estimate.sql
+	BloatEstimate string
+
+	// 使用 pgstattuple 扩展计算表和 btree 索引的膨胀
+	//go:embed sql/bloat_pgstattuple.sql
+	BloatPgstattuple string
+
+	// 查询表的 vacuum / analyze 状态以及事务 ID 回卷风险
+	//go:embed sql/maintenance.sql
+	Maintenance string
+
+	// 查询锁等待关系，使用 pg_blocking_pids，需要 9.6 及以上版本
+	//go:embed sql/blocking_locks.sql
+	blockingLocks string
+
+	// 查询锁等待关系及锁的实际等待时长，使用 pg_locks.waitstart，需要 14 及以上版本
+	//go:embed sql/blocking_locks_waitstart.sql
+	BlockingLocksWaitstart string
+
+	// 通过 pg_locks 关联查询锁等待关系，适用于 9.2 - 9.5 版本
+	//go:embed sql/blocking_locks_legacy.sql
+	blockingLocksLegacy string
+
+	// 主库上的备库复制状态，使用 pg_wal_* 函数，需要 10 及以上版本
+	//go:embed sql/replication_standbys.sql
+	replicationStandbys string
+
+	// 主库上的备库复制状态，使用 pg_xlog_* 函数，适用于 9.2 - 9.6 版本
+	//go:embed sql/replication_standbys_legacy.sql
+	replicationStandbysLegacy string
+
+	// 复制槽及其保留的 WAL 大小，需要 10 及以上版本
+	//go:embed sql/replication_slots.sql
+	replicationSlots string
+
+	// 复制槽及其保留的 WAL 大小，适用于 9.4 - 9.6 版本
+	//go:embed sql/replication_slots_legacy.sql
+	replicationSlotsLegacy string
+
+	// 备库的接收、回放位置及延迟，需要 10 及以上版本
+	//go:embed sql/replication_receiver.sql
+	replicationReceiver string
+
+	// 备库的接收、回放位置及延迟，适用于 9.2 - 9.6 版本
+	//go:embed sql/replication_receiver_legacy.sql
+	replicationReceiverLegacy string
+
+	// 查询角色的属性及（递归的）成员关系，需要 9.5 及以上版本
+	//go:embed sql/roles.sql
+	roles string
+
+	// 查询角色的属性及成员关系，适用于 9.1 - 9.4 版本，不包含 bypass_rls 属性
+	//go:embed sql/roles_legacy.sql
+	rolesLegacy string
+
+	// 根据对象的授权计算每个角色的有效权限，授权查询通过 fmt.Sprintf 填充
+	//go:embed sql/privileges.sql
+	Privileges string
+
+	// 查询默认权限（ALTER DEFAULT PRIVILEGES）
+	//go:embed sql/default_privileges.sql
+	DefaultPrivileges string
+
+	// 基于 pg_depend / pg_rewrite 递归查询对象的依赖关系
+	//go:embed sql/dependencies.sql
+	Dependencies string
+
+	// 查询表中每列的 pg_stats 统计信息
+	//go:embed sql/column_stats.sql
+	ColumnStats string
+
+	// 按名称及注释搜索 schema、表、视图、列、函数、索引及约束
+	//go:embed sql/search.sql
+	Search string
+
+	// 查询可搜索值的表及列，包含列的基础类型及是否为主键
+	//go:embed sql/value_search_columns.sql
+	ValueSearchColumns string
+
+	// 查询表及索引的字节大小和估算行数，用于定期快照
+	//go:embed sql/table_sizes.sql
+	TableSizes string
+
 	// 适配不同版本
 	// Activity queries for specific PG versions
 	Activity = map[string]string{
@@ -67,4 +194,59 @@ var (
 		"9.5":     "SELECT datname, query, state, waiting, query_start, state_change, pid, datid, application_name, client_addr FROM pg_stat_activity WHERE datname = current_database()",
 		"9.6":     "SELECT datname, query, state, wait_event, wait_event_type, query_start, state_change, pid, datid, application_name, client_addr FROM pg_stat_activity WHERE datname = current_database()",
 	}
+
+	// Blocking locks queries for specific PG versions, 9.1 is not supported
+	BlockingLocks = map[string]string{
+		"default": blockingLocks,
+		"9.2":     blockingLocksLegacy,
+		"9.3":     blockingLocksLegacy,
+		"9.4":     blockingLocksLegacy,
+		"9.5":     blockingLocksLegacy,
+	}
+
+	// Replication statistics queries for specific PG versions, 9.1 is not supported
+	ReplicationStandbys = map[string]string{
+		"default": replicationStandbys,
+		"9.2":     replicationStandbysLegacy,
+		"9.3":     replicationStandbysLegacy,
+		"9.4":     replicationStandbysLegacy,
+		"9.5":     replicationStandbysLegacy,
+		"9.6":     replicationStandbysLegacy,
+	}
+
+	// Replication slots queries for specific PG versions, slots are available since 9.4
+	ReplicationSlots = map[string]string{
+		"default": replicationSlots,
+		"9.4":     replicationSlotsLegacy,
+		"9.5":     replicationSlotsLegacy,
+		"9.6":     replicationSlotsLegacy,
+	}
+
+	// Standby receiver queries for specific PG versions, 9.1 is not supported
+	ReplicationReceiver = map[string]string{
+		"default": replicationReceiver,
+		"9.2":     replicationReceiverLegacy,
+		"9.3":     replicationReceiverLegacy,
+		"9.4":     replicationReceiverLegacy,
+		"9.5":     replicationReceiverLegacy,
+		"9.6":     replicationReceiverLegacy,
+	}
+
+	// Roles queries for specific PG versions, bypass_rls is available since 9.5
+	Roles = map[string]string{
+		"default": roles,
+		"9.1":     rolesLegacy,
+		"9.2":     rolesLegacy,
+		"9.3":     rolesLegacy,
+		"9.4":     rolesLegacy,
+	}
+
+	// Settings explorer queries for specific PG versions, pending_restart is available since 9.5
+	SettingsExplorer = map[string]string{
+		"default": settingsExplorer,
+		"9.1":     settingsExplorerLegacy,
+		"9.2":     settingsExplorerLegacy,
+		"9.3":     settingsExplorerLegacy,
+		"9.4":     settingsExplorerLegacy,
+	}
 )
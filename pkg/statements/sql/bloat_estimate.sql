@@ -0,0 +1,213 @@
+WITH table_stats AS (
+  SELECT
+    tbl.oid AS tblid,
+    ns.nspname AS schema_name,
+    tbl.relname AS table_name,
+    tbl.reltuples,
+    tbl.relpages AS heappages,
+    COALESCE(toast.relpages, 0) AS toastpages,
+    COALESCE(toast.reltuples, 0) AS toasttuples,
+    COALESCE(substring(array_to_string(tbl.reloptions, ' ') FROM 'fillfactor=([0-9]+)')::smallint, 100) AS fillfactor,
+    current_setting('block_size')::numeric AS bs,
+    CASE WHEN version() ~ 'mingw32' OR version() ~ '64-bit|x86_64|ppc64|ia64|amd64' THEN 8 ELSE 4 END AS ma,
+    24 AS page_hdr,
+    23 + CASE WHEN MAX(COALESCE(s.null_frac, 0)) > 0 THEN (7 + count(s.attname)) / 8 ELSE 0::int END AS tpl_hdr_size,
+    sum((1 - COALESCE(s.null_frac, 0)) * COALESCE(s.avg_width, 0)) AS tpl_data_size,
+    bool_or(att.atttypid = 'pg_catalog.name'::regtype)
+      OR sum(CASE WHEN att.attnum > 0 THEN 1 ELSE 0 END) <> count(s.attname) AS is_na
+  FROM
+    pg_attribute AS att
+  JOIN pg_class AS tbl
+    ON att.attrelid = tbl.oid
+  JOIN pg_namespace AS ns
+    ON ns.oid = tbl.relnamespace
+  LEFT JOIN pg_stats AS s
+    ON s.schemaname = ns.nspname
+    AND s.tablename = tbl.relname
+    AND s.inherited = false
+    AND s.attname = att.attname
+  LEFT JOIN pg_class AS toast
+    ON tbl.reltoastrelid = toast.oid
+  WHERE
+    NOT att.attisdropped
+    AND att.attnum > 0
+    AND tbl.relkind IN ('r', 'm')
+    AND tbl.reltuples >= 0
+    AND ns.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  GROUP BY
+    1, 2, 3, 4, 5, 6, 7, 8, 9, 10
+),
+table_sizes AS (
+  SELECT
+    schema_name,
+    table_name,
+    heappages + toastpages AS tblpages,
+    bs,
+    fillfactor,
+    reltuples,
+    toasttuples,
+    is_na,
+    (
+      4 + tpl_hdr_size + tpl_data_size + (2 * ma)
+      - CASE WHEN tpl_hdr_size % ma = 0 THEN ma ELSE tpl_hdr_size % ma END
+      - CASE WHEN ceil(tpl_data_size)::int % ma = 0 THEN ma ELSE ceil(tpl_data_size)::int % ma END
+    ) AS tpl_size,
+    page_hdr
+  FROM
+    table_stats
+),
+table_bloat AS (
+  SELECT
+    schema_name,
+    table_name,
+    tblpages,
+    bs,
+    is_na,
+    ceil(reltuples / ((bs - page_hdr) * fillfactor / (tpl_size * 100))) + ceil(toasttuples / 4) AS est_tblpages_ff
+  FROM
+    table_sizes
+),
+index_columns AS (
+  SELECT
+    ct.relnamespace,
+    ct.relname AS table_name,
+    ic.index_name,
+    ic.reltuples,
+    ic.relpages,
+    ic.idxoid,
+    ic.fillfactor,
+    COALESCE(a1.attname, a2.attname) AS attname,
+    COALESCE(a1.atttypid, a2.atttypid) AS atttypid,
+    CASE WHEN a1.attnum IS NULL THEN ic.index_name ELSE ct.relname END AS attrelname
+  FROM (
+    SELECT
+      index_name,
+      reltuples,
+      relpages,
+      tbloid,
+      idxoid,
+      fillfactor,
+      indkey,
+      generate_series(1, indnatts) AS attpos
+    FROM (
+      SELECT
+        ci.relname AS index_name,
+        ci.reltuples,
+        ci.relpages,
+        i.indrelid AS tbloid,
+        i.indexrelid AS idxoid,
+        COALESCE(substring(array_to_string(ci.reloptions, ' ') FROM 'fillfactor=([0-9]+)')::smallint, 90) AS fillfactor,
+        i.indnatts,
+        string_to_array(textin(int2vectorout(i.indkey)), ' ')::int[] AS indkey
+      FROM
+        pg_index i
+      JOIN pg_class ci
+        ON ci.oid = i.indexrelid
+      WHERE
+        ci.relam = (SELECT oid FROM pg_am WHERE amname = 'btree')
+        AND ci.relpages > 0
+        AND ci.reltuples >= 0
+    ) AS idx_data
+  ) AS ic
+  JOIN pg_class ct
+    ON ct.oid = ic.tbloid
+  LEFT JOIN pg_attribute a1
+    ON ic.indkey[ic.attpos] <> 0
+    AND a1.attrelid = ic.tbloid
+    AND a1.attnum = ic.indkey[ic.attpos]
+  LEFT JOIN pg_attribute a2
+    ON ic.indkey[ic.attpos] = 0
+    AND a2.attrelid = ic.idxoid
+    AND a2.attnum = ic.attpos
+),
+index_stats AS (
+  SELECT
+    n.nspname AS schema_name,
+    i.table_name,
+    i.index_name,
+    i.reltuples,
+    i.relpages,
+    i.idxoid,
+    i.fillfactor,
+    current_setting('block_size')::numeric AS bs,
+    CASE WHEN version() ~ 'mingw32' OR version() ~ '64-bit|x86_64|ppc64|ia64|amd64' THEN 8 ELSE 4 END AS maxalign,
+    24 AS pagehdr,
+    16 AS pageopqdata,
+    CASE WHEN max(COALESCE(s.null_frac, 0)) = 0 THEN 8 ELSE 8 + ((32 + 8 - 1) / 8) END AS index_tuple_hdr_bm,
+    sum((1 - COALESCE(s.null_frac, 0)) * COALESCE(s.avg_width, 1024)) AS nulldatawidth,
+    max(CASE WHEN i.atttypid = 'pg_catalog.name'::regtype THEN 1 ELSE 0 END) > 0 AS is_na
+  FROM
+    index_columns i
+  JOIN pg_namespace n
+    ON n.oid = i.relnamespace
+  JOIN pg_stats s
+    ON s.schemaname = n.nspname
+    AND s.tablename = i.attrelname
+    AND s.attname = i.attname
+  WHERE
+    n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  GROUP BY
+    1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11
+),
+index_sizes AS (
+  SELECT
+    schema_name,
+    table_name,
+    index_name,
+    reltuples,
+    relpages,
+    fillfactor,
+    bs,
+    pagehdr,
+    pageopqdata,
+    is_na,
+    (
+      index_tuple_hdr_bm + maxalign
+      - CASE WHEN index_tuple_hdr_bm % maxalign = 0 THEN maxalign ELSE index_tuple_hdr_bm % maxalign END
+      + nulldatawidth + maxalign
+      - CASE
+          WHEN nulldatawidth = 0 THEN 0
+          WHEN nulldatawidth::integer % maxalign = 0 THEN maxalign
+          ELSE nulldatawidth::integer % maxalign
+        END
+    )::numeric AS nulldatahdrwidth
+  FROM
+    index_stats
+),
+index_bloat AS (
+  SELECT
+    schema_name,
+    table_name,
+    index_name,
+    relpages,
+    bs,
+    is_na,
+    COALESCE(1 + ceil(reltuples / floor((bs - pageopqdata - pagehdr) * fillfactor / (100 * (4 + nulldatahdrwidth)::float))), 0) AS est_pages_ff
+  FROM
+    index_sizes
+)
+SELECT
+  'table' AS type,
+  schema_name,
+  table_name,
+  NULL::name AS index_name,
+  (bs * tblpages)::bigint AS real_size_bytes,
+  CASE WHEN tblpages > est_tblpages_ff THEN ((tblpages - est_tblpages_ff) * bs)::bigint ELSE 0 END AS bloat_bytes
+FROM
+  table_bloat
+WHERE
+  NOT is_na
+
+UNION ALL
+
+SELECT
+  'index',
+  schema_name,
+  table_name,
+  index_name,
+  (bs * relpages)::bigint,
+  CASE WHEN relpages > est_pages_ff THEN ((relpages - est_pages_ff) * bs)::bigint ELSE 0 END
+FROM
+  index_bloat
+WHERE
+  NOT is_na
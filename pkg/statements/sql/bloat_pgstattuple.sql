@@ -0,0 +1,42 @@
+SELECT
+  'table' AS type,
+  n.nspname AS schema_name,
+  c.relname AS table_name,
+  NULL::name AS index_name,
+  s.table_len AS real_size_bytes,
+  (s.dead_tuple_len + s.free_space)::bigint AS bloat_bytes
+FROM
+  pg_class c
+JOIN pg_namespace n
+  ON n.oid = c.relnamespace
+CROSS JOIN LATERAL pgstattuple(quote_ident(n.nspname) || '.' || quote_ident(c.relname)) s
+WHERE
+  c.relkind IN ('r', 'm')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+
+UNION ALL
+
+SELECT
+  'index',
+  n.nspname,
+  t.relname,
+  c.relname,
+  s.index_size,
+  CASE
+    WHEN s.leaf_pages > 0 AND s.avg_leaf_density = s.avg_leaf_density
+      THEN (s.index_size * (100 - s.avg_leaf_density) / 100)::bigint
+    ELSE 0
+  END
+FROM
+  pg_index i
+JOIN pg_class c
+  ON c.oid = i.indexrelid
+JOIN pg_class t
+  ON t.oid = i.indrelid
+JOIN pg_namespace n
+  ON n.oid = c.relnamespace
+CROSS JOIN LATERAL pgstatindex(quote_ident(n.nspname) || '.' || quote_ident(c.relname)) s
+WHERE
+  c.relam = (SELECT oid FROM pg_am WHERE amname = 'btree')
+  AND c.relpages > 0
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
@@ -0,0 +1,50 @@
+WITH procs AS (
+  SELECT
+    pid,
+    pg_blocking_pids(pid) AS blocked_by,
+    usename,
+    application_name,
+    state,
+    wait_event_type,
+    wait_event,
+    query,
+    query_start,
+    state_change
+  FROM
+    pg_stat_activity
+  WHERE
+    datname = current_database()
+)
+SELECT
+  procs.pid,
+  procs.blocked_by,
+  COALESCE(procs.usename::text, '') AS username,
+  COALESCE(procs.application_name, '') AS application_name,
+  COALESCE(procs.state, '') AS state,
+  COALESCE(procs.wait_event_type || ': ' || procs.wait_event, '') AS wait_event,
+  COALESCE(waiting.locktype, '') AS lock_type,
+  COALESCE(waiting.mode, '') AS lock_mode,
+  COALESCE(waiting.relation::regclass::text, '') AS relation,
+  COALESCE((
+    SELECT string_agg(DISTINCT held.mode || ' on ' || held.relation::regclass::text, ', ')
+    FROM pg_locks held
+    WHERE held.pid = procs.pid AND held.granted AND held.relation IS NOT NULL
+  ), '') AS held_locks,
+  COALESCE(procs.query, '') AS query,
+  COALESCE(EXTRACT(EPOCH FROM now() - procs.query_start), 0)::float8 AS query_duration,
+  CASE
+    WHEN waiting.pid IS NOT NULL THEN EXTRACT(EPOCH FROM now() - procs.state_change)::float8
+  END AS state_duration
+FROM
+  procs
+LEFT JOIN LATERAL (
+  SELECT pid, locktype, mode, relation
+  FROM pg_locks
+  WHERE pg_locks.pid = procs.pid AND NOT pg_locks.granted
+  LIMIT 1
+) waiting ON true
+WHERE
+  array_length(procs.blocked_by, 1) > 0
+  OR procs.pid IN (SELECT unnest(blocked_by) FROM procs)
+ORDER BY
+  procs.pid
@@ -0,0 +1,62 @@
+WITH blocking AS (
+  SELECT
+    blocked.pid,
+    array_agg(DISTINCT blocker.pid) AS blocked_by
+  FROM
+    pg_locks blocked
+  JOIN pg_locks blocker
+    ON blocker.granted
+    AND blocker.pid <> blocked.pid
+    AND blocker.locktype = blocked.locktype
+    AND blocker.database IS NOT DISTINCT FROM blocked.database
+    AND blocker.relation IS NOT DISTINCT FROM blocked.relation
+    AND blocker.page IS NOT DISTINCT FROM blocked.page
+    AND blocker.tuple IS NOT DISTINCT FROM blocked.tuple
+    AND blocker.virtualxid IS NOT DISTINCT FROM blocked.virtualxid
+    AND blocker.transactionid IS NOT DISTINCT FROM blocked.transactionid
+    AND blocker.classid IS NOT DISTINCT FROM blocked.classid
+    AND blocker.objid IS NOT DISTINCT FROM blocked.objid
+    AND blocker.objsubid IS NOT DISTINCT FROM blocked.objsubid
+  WHERE
+    NOT blocked.granted
+  GROUP BY
+    blocked.pid
+)
+SELECT
+  activity.pid,
+  COALESCE(blocking.blocked_by, '{}'::int[]) AS blocked_by,
+  COALESCE(activity.usename::text, '') AS username,
+  COALESCE(activity.application_name, '') AS application_name,
+  COALESCE(activity.state, '') AS state,
+  CASE WHEN activity.waiting THEN 'Lock' ELSE '' END AS wait_event,
+  COALESCE((
+    SELECT locktype FROM pg_locks WHERE pid = activity.pid AND NOT granted LIMIT 1
+  ), '') AS lock_type,
+  COALESCE((
+    SELECT mode FROM pg_locks WHERE pid = activity.pid AND NOT granted LIMIT 1
+  ), '') AS lock_mode,
+  COALESCE((
+    SELECT relation::regclass::text FROM pg_locks WHERE pid = activity.pid AND NOT granted LIMIT 1
+  ), '') AS relation,
+  COALESCE((
+    SELECT string_agg(DISTINCT held.mode || ' on ' || held.relation::regclass::text, ', ')
+    FROM pg_locks held
+    WHERE held.pid = activity.pid AND held.granted AND held.relation IS NOT NULL
+  ), '') AS held_locks,
+  COALESCE(activity.query, '') AS query,
+  COALESCE(EXTRACT(EPOCH FROM now() - activity.query_start), 0)::float8 AS query_duration,
+  CASE
+    WHEN activity.waiting THEN EXTRACT(EPOCH FROM now() - activity.state_change)::float8
+  END AS state_duration
+FROM
+  pg_stat_activity activity
+LEFT JOIN blocking
+  ON blocking.pid = activity.pid
+WHERE
+  activity.datname = current_database()
+  AND (
+    blocking.pid IS NOT NULL
+    OR activity.pid IN (SELECT unnest(blocked_by) FROM blocking)
+  )
+ORDER BY
+  activity.pid
@@ -0,0 +1,27 @@
+SELECT
+  a.attname AS column_name,
+  pg_catalog.format_type(a.atttypid, a.atttypmod) AS data_type,
+  s.null_frac,
+  s.avg_width,
+  s.n_distinct,
+  s.most_common_vals::text AS most_common_vals,
+  s.most_common_freqs::text AS most_common_freqs,
+  s.histogram_bounds::text AS histogram_bounds,
+  s.correlation
+FROM
+  pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_catalog.pg_stats s
+  ON s.schemaname = n.nspname
+  AND s.tablename = c.relname
+  AND s.attname = a.attname
+  -- Partitioned tables only have statistics collected across partitions
+  AND s.inherited = (c.relkind = 'p')
+WHERE
+  n.nspname = $1
+  AND c.relname = $2
+  AND a.attnum > 0
+  AND NOT a.attisdropped
+ORDER BY
+  a.attnum
@@ -0,0 +1,23 @@
+SELECT
+  pg_get_userbyid(d.defaclrole)::text AS owner,
+  n.nspname::text AS schema_name,
+  CASE d.defaclobjtype
+    WHEN 'r' THEN 'table'
+    WHEN 'S' THEN 'sequence'
+    WHEN 'f' THEN 'function'
+    WHEN 'T' THEN 'type'
+    WHEN 'n' THEN 'schema'
+  END AS object_type,
+  CASE WHEN (d.acl).grantee = 0 THEN 'PUBLIC' ELSE pg_get_userbyid((d.acl).grantee)::text END AS grantee,
+  (d.acl).privilege_type AS privilege_type,
+  (d.acl).is_grantable AS is_grantable,
+  pg_get_userbyid((d.acl).grantor)::text AS grantor
+FROM
+  (SELECT defaclrole, defaclnamespace, defaclobjtype, aclexplode(defaclacl) AS acl FROM pg_default_acl) d
+LEFT JOIN pg_namespace n ON n.oid = d.defaclnamespace
+ORDER BY
+  owner,
+  schema_name NULLS FIRST,
+  object_type,
+  grantee,
+  privilege_type
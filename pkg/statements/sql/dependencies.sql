@@ -0,0 +1,115 @@
+WITH RECURSIVE edges AS (
+  -- View dependencies are recorded for their rewrite rules, attribute them to the views
+  SELECT DISTINCT
+    CASE WHEN d.classid = 'pg_catalog.pg_rewrite'::regclass THEN 'pg_catalog.pg_class'::regclass::oid ELSE d.classid END AS classid,
+    COALESCE(r.ev_class, d.objid) AS objid,
+    CASE WHEN d.classid = 'pg_catalog.pg_rewrite'::regclass THEN 0 ELSE d.objsubid END AS objsubid,
+    d.refclassid,
+    d.refobjid,
+    d.refobjsubid,
+    d.deptype
+  FROM
+    pg_catalog.pg_depend d
+  LEFT JOIN pg_catalog.pg_rewrite r ON d.classid = 'pg_catalog.pg_rewrite'::regclass AND r.oid = d.objid
+  WHERE
+    d.deptype IN ('n', 'a')
+    AND d.refclassid <> 'pg_catalog.pg_namespace'::regclass
+),
+directed AS (
+  SELECT
+    'dependents' AS direction,
+    classid AS node_classid,
+    objid AS node_objid,
+    objsubid AS node_objsubid,
+    refclassid AS target_classid,
+    refobjid AS target_objid,
+    refobjsubid AS target_objsubid,
+    deptype
+  FROM
+    edges
+  WHERE
+    NOT (classid = refclassid AND objid = refobjid)
+  UNION ALL
+  SELECT
+    'depends_on',
+    refclassid,
+    refobjid,
+    refobjsubid,
+    classid,
+    objid,
+    objsubid,
+    deptype
+  FROM
+    edges
+  WHERE
+    NOT (classid = refclassid AND objid = refobjid)
+),
+tree AS (
+  SELECT
+    e.node_classid,
+    e.node_objid,
+    e.node_objsubid,
+    e.deptype,
+    ''::text AS parent_id,
+    1 AS depth,
+    ARRAY[$1::regclass::oid::text || ':' || $2::oid::text, e.node_classid::text || ':' || e.node_objid::text] AS path
+  FROM
+    directed e
+  WHERE
+    e.direction = $4
+    AND e.target_classid = $1::regclass
+    AND e.target_objid = $2::oid
+    AND ($3 = 0 OR e.target_objsubid = $3)
+  UNION ALL
+  SELECT
+    e.node_classid,
+    e.node_objid,
+    e.node_objsubid,
+    e.deptype,
+    t.node_classid::text || ':' || t.node_objid::text || ':' || t.node_objsubid::text,
+    t.depth + 1,
+    t.path || (e.node_classid::text || ':' || e.node_objid::text)
+  FROM
+    tree t
+  JOIN directed e ON e.target_classid = t.node_classid AND e.target_objid = t.node_objid
+  WHERE
+    e.direction = $4
+    AND t.depth < 10
+    AND NOT (e.node_classid::text || ':' || e.node_objid::text) = ANY(t.path)
+)
+SELECT DISTINCT
+  t.node_classid::text || ':' || t.node_objid::text || ':' || t.node_objsubid::text AS id,
+  t.parent_id,
+  CASE
+    WHEN t.node_classid = 'pg_catalog.pg_class'::regclass AND t.node_objsubid <> 0 THEN 'column'
+    WHEN t.node_classid = 'pg_catalog.pg_class'::regclass THEN
+      CASE c.relkind
+        WHEN 'r' THEN 'table'
+        WHEN 'p' THEN 'partitioned_table'
+        WHEN 'v' THEN 'view'
+        WHEN 'm' THEN 'materialized_view'
+        WHEN 'i' THEN 'index'
+        WHEN 'I' THEN 'index'
+        WHEN 'S' THEN 'sequence'
+        WHEN 'f' THEN 'foreign_table'
+        WHEN 'c' THEN 'type'
+        ELSE 'relation'
+      END
+    ELSE
+      CASE t.node_classid::regclass::text
+        WHEN 'pg_proc' THEN 'function'
+        WHEN 'pg_attrdef' THEN 'default'
+        ELSE regexp_replace(t.node_classid::regclass::text, '^pg_', '')
+      END
+  END AS kind,
+  t.node_objid::text AS oid,
+  COALESCE(pg_catalog.pg_describe_object(t.node_classid, t.node_objid, t.node_objsubid), '') AS name,
+  CASE t.deptype WHEN 'n' THEN 'normal' WHEN 'a' THEN 'auto' END AS dependency_type
+FROM
+  tree t
+LEFT JOIN pg_catalog.pg_class c ON t.node_classid = 'pg_catalog.pg_class'::regclass AND c.oid = t.node_objid
+ORDER BY
+  kind,
+  name,
+  id,
+  parent_id
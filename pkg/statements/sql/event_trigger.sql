@@ -0,0 +1,19 @@
+SELECT
+  e.oid,
+  e.evtname AS trigger_name,
+  e.evtevent AS event,
+  pg_catalog.pg_get_userbyid(e.evtowner) AS owner,
+  e.evtfoid::regprocedure::text AS function_name,
+  CASE e.evtenabled
+    WHEN 'O' THEN 'origin'
+    WHEN 'D' THEN 'disabled'
+    WHEN 'R' THEN 'replica'
+    WHEN 'A' THEN 'always'
+  END AS enabled,
+  array_to_string(e.evttags, ', ') AS tags,
+  pg_catalog.pg_get_functiondef(e.evtfoid) AS function_definition,
+  pg_catalog.obj_description(e.oid, 'pg_event_trigger') AS comment
+FROM
+  pg_catalog.pg_event_trigger e
+WHERE
+  e.oid = $1::oid
@@ -0,0 +1,18 @@
+SELECT
+  e.oid,
+  e.evtname AS trigger_name,
+  e.evtevent AS event,
+  pg_catalog.pg_get_userbyid(e.evtowner) AS owner,
+  e.evtfoid::regprocedure::text AS function_name,
+  CASE e.evtenabled
+    WHEN 'O' THEN 'origin'
+    WHEN 'D' THEN 'disabled'
+    WHEN 'R' THEN 'replica'
+    WHEN 'A' THEN 'always'
+  END AS enabled,
+  array_to_string(e.evttags, ', ') AS tags,
+  pg_catalog.obj_description(e.oid, 'pg_event_trigger') AS comment
+FROM
+  pg_catalog.pg_event_trigger e
+ORDER BY
+  e.evtname
@@ -0,0 +1,20 @@
+SELECT
+  e.oid,
+  e.extname AS extension_name,
+  e.extversion AS installed_version,
+  a.default_version,
+  n.nspname AS schema_name,
+  e.extrelocatable AS relocatable,
+  pg_catalog.pg_get_userbyid(e.extowner) AS owner,
+  (
+    SELECT count(*)
+    FROM pg_catalog.pg_depend d
+    WHERE d.refclassid = 'pg_catalog.pg_extension'::regclass AND d.refobjid = e.oid AND d.deptype = 'e'
+  ) AS member_objects,
+  pg_catalog.obj_description(e.oid, 'pg_extension') AS comment
+FROM
+  pg_catalog.pg_extension e
+JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+LEFT JOIN pg_catalog.pg_available_extensions a ON a.name = e.extname
+WHERE
+  e.oid = $1::oid
@@ -0,0 +1,18 @@
+SELECT
+  c.oid,
+  n.nspname AS schema_name,
+  c.relname AS table_name,
+  s.srvname AS server_name,
+  w.fdwname AS wrapper_name,
+  array_to_string(ft.ftoptions, ', ') AS table_options,
+  array_to_string(s.srvoptions, ', ') AS server_options,
+  pg_catalog.pg_get_userbyid(c.relowner) AS owner,
+  pg_catalog.obj_description(c.oid) AS comment
+FROM
+  pg_catalog.pg_foreign_table ft
+JOIN pg_catalog.pg_class c ON c.oid = ft.ftrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_catalog.pg_foreign_server s ON s.oid = ft.ftserver
+JOIN pg_catalog.pg_foreign_data_wrapper w ON w.oid = s.srvfdw
+WHERE
+  c.oid = $1::oid
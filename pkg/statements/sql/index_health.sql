@@ -0,0 +1,151 @@
+WITH indexes AS (
+  SELECT
+    i.indexrelid,
+    i.indrelid,
+    i.indkey,
+    i.indclass,
+    i.indisunique,
+    i.indisprimary,
+    i.indisvalid,
+    COALESCE(pg_get_expr(i.indexprs, i.indrelid), '') AS exprs,
+    COALESCE(pg_get_expr(i.indpred, i.indrelid), '') AS pred,
+    ic.relam,
+    n.nspname AS schema_name,
+    t.relname AS table_name,
+    ic.relname AS index_name
+  FROM
+    pg_index i
+  JOIN pg_class ic
+    ON ic.oid = i.indexrelid
+  JOIN pg_class t
+    ON t.oid = i.indrelid
+  JOIN pg_namespace n
+    ON n.oid = t.relnamespace
+  WHERE
+    n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+),
+issues AS (
+  SELECT
+    'unused' AS issue,
+    idx.schema_name,
+    idx.table_name,
+    idx.index_name,
+    idx.indexrelid,
+    'index has never been scanned' AS details
+  FROM
+    indexes idx
+  JOIN pg_stat_user_indexes s
+    ON s.indexrelid = idx.indexrelid
+  WHERE
+    s.idx_scan = 0
+    AND NOT idx.indisunique
+    AND NOT idx.indisprimary
+
+  UNION ALL
+
+  SELECT
+    'invalid',
+    idx.schema_name,
+    idx.table_name,
+    idx.index_name,
+    idx.indexrelid,
+    'index is invalid, likely left by a failed concurrent index build'
+  FROM
+    indexes idx
+  WHERE
+    NOT idx.indisvalid
+
+  UNION ALL
+
+  SELECT
+    'duplicate',
+    a.schema_name,
+    a.table_name,
+    a.index_name,
+    a.indexrelid,
+    'same definition as ' || b.index_name
+  FROM
+    indexes a
+  JOIN indexes b
+    ON b.indrelid = a.indrelid
+    AND b.indexrelid < a.indexrelid
+    AND b.relam = a.relam
+    AND b.indkey::text = a.indkey::text
+    AND b.indclass::text = a.indclass::text
+    AND b.exprs = a.exprs
+    AND b.pred = a.pred
+  WHERE
+    NOT a.indisprimary
+
+  UNION ALL
+
+  SELECT
+    'redundant',
+    a.schema_name,
+    a.table_name,
+    a.index_name,
+    a.indexrelid,
+    'columns are a prefix of ' || b.index_name
+  FROM
+    indexes a
+  JOIN indexes b
+    ON b.indrelid = a.indrelid
+    AND b.indexrelid <> a.indexrelid
+    AND b.relam = a.relam
+    AND b.indkey::text LIKE a.indkey::text || ' %'
+    AND b.indclass::text LIKE a.indclass::text || ' %'
+    AND b.exprs = ''
+    AND b.pred = ''
+  WHERE
+    a.exprs = ''
+    AND a.pred = ''
+    AND NOT a.indisunique
+    AND NOT a.indisprimary
+
+  UNION ALL
+
+  SELECT
+    'missing_fk_index',
+    n.nspname,
+    t.relname,
+    NULL,
+    NULL,
+    'foreign key ' || c.conname || ' (' || (
+      SELECT string_agg(a.attname, ', ' ORDER BY k.pos)
+      FROM generate_subscripts(c.conkey, 1) AS k(pos)
+      JOIN pg_attribute a
+        ON a.attrelid = c.conrelid AND a.attnum = c.conkey[k.pos]
+    ) || ') has no supporting index'
+  FROM
+    pg_constraint c
+  JOIN pg_class t
+    ON t.oid = c.conrelid
+  JOIN pg_namespace n
+    ON n.oid = t.relnamespace
+  WHERE
+    c.contype = 'f'
+    AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+    AND NOT EXISTS (
+      SELECT 1
+      FROM pg_index i
+      WHERE
+        i.indrelid = c.conrelid
+        AND (i.indkey::int2[])[0:array_length(c.conkey, 1) - 1] @> c.conkey
+    )
+)
+SELECT
+  issue,
+  schema_name,
+  table_name,
+  index_name,
+  pg_size_pretty(pg_relation_size(indexrelid)) AS index_size,
+  pg_relation_size(indexrelid) AS index_size_bytes,
+  details
+FROM
+  issues
+ORDER BY
+  issue,
+  pg_relation_size(indexrelid) DESC NULLS LAST,
+  schema_name,
+  table_name,
+  index_name
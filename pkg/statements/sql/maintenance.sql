@@ -0,0 +1,54 @@
+WITH settings AS (
+  SELECT current_setting('autovacuum_freeze_max_age')::bigint AS freeze_max_age
+),
+tables AS (
+  SELECT
+    s.relid,
+    s.schemaname AS schema_name,
+    s.relname AS table_name,
+    s.n_live_tup,
+    s.n_dead_tup,
+    s.last_vacuum,
+    s.last_autovacuum,
+    s.last_analyze,
+    s.last_autoanalyze,
+    s.vacuum_count,
+    s.autovacuum_count,
+    s.analyze_count,
+    s.autoanalyze_count,
+    GREATEST(age(c.relfrozenxid), COALESCE(age(t.relfrozenxid), 0)) AS xid_age
+  FROM
+    pg_stat_user_tables s
+  JOIN pg_class c
+    ON c.oid = s.relid
+  LEFT JOIN pg_class t
+    ON t.oid = c.reltoastrelid
+)
+SELECT
+  tables.schema_name,
+  tables.table_name,
+  tables.n_live_tup AS live_tuples,
+  tables.n_dead_tup AS dead_tuples,
+  ROUND(100.0 * tables.n_dead_tup / NULLIF(tables.n_live_tup + tables.n_dead_tup, 0), 2) AS dead_ratio,
+  tables.last_vacuum,
+  tables.last_autovacuum,
+  tables.last_analyze,
+  tables.last_autoanalyze,
+  tables.vacuum_count,
+  tables.autovacuum_count,
+  tables.analyze_count,
+  tables.autoanalyze_count,
+  tables.xid_age,
+  ROUND(100.0 * tables.xid_age / settings.freeze_max_age, 2) AS freeze_max_age_pct,
+  CASE
+    WHEN tables.xid_age > 1500000000 THEN 'critical'
+    WHEN tables.xid_age > settings.freeze_max_age THEN 'high'
+    WHEN tables.xid_age > settings.freeze_max_age * 0.75 THEN 'medium'
+    ELSE 'low'
+  END AS wraparound_risk
+FROM
+  tables
+CROSS JOIN settings
+ORDER BY
+  tables.xid_age DESC,
+  tables.n_dead_tup DESC
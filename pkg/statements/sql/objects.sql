@@ -11,15 +11,22 @@ WITH all_objects AS (
       WHEN 'S' THEN 'sequence'
       WHEN 's' THEN 'special'
       WHEN 'f' THEN 'foreign_table'
+      WHEN 'p' THEN 'partitioned_table'
     END AS type,
     pg_catalog.pg_get_userbyid(c.relowner) AS owner,
-    pg_catalog.obj_description(c.oid) AS comment
+    pg_catalog.obj_description(c.oid) AS comment,
+    (
+      SELECT i.inhparent::text
+      FROM pg_catalog.pg_inherits i
+      JOIN pg_catalog.pg_class pc ON pc.oid = i.inhparent
+      WHERE i.inhrelid = c.oid AND pc.relkind = 'p'
+    ) AS parent
   FROM
     pg_catalog.pg_class c
   LEFT JOIN
     pg_catalog.pg_namespace n ON n.oid = c.relnamespace
   WHERE
-    c.relkind IN ('r','v','m','S','s','')
+    c.relkind IN ('r','v','m','S','s','f','p','')
     AND n.nspname !~ '^pg_(toast|temp)'
     AND n.nspname NOT IN ('information_schema', 'pg_catalog')
     AND has_schema_privilege(n.nspname, 'USAGE')
@@ -32,7 +39,8 @@ WITH all_objects AS (
     p.proname AS name,
     'function' AS function,
     pg_catalog.pg_get_userbyid(p.proowner) AS owner,
-    NULL AS comment
+    NULL AS comment,
+    NULL AS parent
   FROM
     pg_catalog.pg_namespace n
   JOIN
@@ -40,6 +48,68 @@ WITH all_objects AS (
   WHERE
     n.nspname !~ '^pg_(toast|temp)'
     AND n.nspname NOT IN ('information_schema', 'pg_catalog')
+
+  UNION
+
+  SELECT
+    t.oid,
+    n.nspname AS schema,
+    t.tgname AS name,
+    'trigger' AS type,
+    pg_catalog.pg_get_userbyid(c.relowner) AS owner,
+    pg_catalog.obj_description(t.oid, 'pg_trigger') AS comment,
+    c.oid::text AS parent
+  FROM
+    pg_catalog.pg_trigger t
+  JOIN
+    pg_catalog.pg_class c ON c.oid = t.tgrelid
+  JOIN
+    pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+  WHERE
+    NOT t.tgisinternal
+    AND n.nspname !~ '^pg_(toast|temp)'
+    AND n.nspname NOT IN ('information_schema', 'pg_catalog')
+    AND has_schema_privilege(n.nspname, 'USAGE')
+
+  UNION
+
+  SELECT
+    t.oid,
+    n.nspname AS schema,
+    t.typname AS name,
+    'type' AS type,
+    pg_catalog.pg_get_userbyid(t.typowner) AS owner,
+    pg_catalog.obj_description(t.oid, 'pg_type') AS comment,
+    NULL AS parent
+  FROM
+    pg_catalog.pg_type t
+  JOIN
+    pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+  LEFT JOIN
+    pg_catalog.pg_class c ON c.oid = t.typrelid
+  WHERE
+    (t.typtype IN ('e', 'd', 'r') OR (t.typtype = 'c' AND c.relkind = 'c'))
+    AND n.nspname !~ '^pg_(toast|temp)'
+    AND n.nspname NOT IN ('information_schema', 'pg_catalog')
+    AND has_schema_privilege(n.nspname, 'USAGE')
+
+  UNION
+
+  SELECT
+    e.oid,
+    n.nspname AS schema,
+    e.extname AS name,
+    'extension' AS type,
+    pg_catalog.pg_get_userbyid(e.extowner) AS owner,
+    pg_catalog.obj_description(e.oid, 'pg_extension') AS comment,
+    NULL AS parent
+  FROM
+    pg_catalog.pg_extension e
+  JOIN
+    pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+  WHERE
+    n.nspname !~ '^pg_(toast|temp)'
+    AND n.nspname NOT IN ('information_schema', 'pg_catalog')
 )
 SELECT * FROM all_objects
 ORDER BY 2, 3
@@ -0,0 +1,42 @@
+WITH RECURSIVE partitions AS (
+  SELECT
+    i.inhrelid AS oid,
+    i.inhparent AS parent,
+    1 AS level
+  FROM
+    pg_catalog.pg_inherits i
+  WHERE
+    i.inhparent = $1::oid
+  UNION ALL
+  SELECT
+    i.inhrelid,
+    i.inhparent,
+    p.level + 1
+  FROM
+    partitions p
+  JOIN pg_catalog.pg_inherits i ON i.inhparent = p.oid
+)
+SELECT
+  p.oid,
+  p.level,
+  n.nspname AS schema_name,
+  c.relname AS partition_name,
+  pc.relname AS parent_name,
+  pg_catalog.pg_get_partkeydef($1::oid) AS partition_key,
+  pg_catalog.pg_get_expr(c.relpartbound, c.oid) AS partition_bound,
+  CASE c.relkind
+    WHEN 'r' THEN 'table'
+    WHEN 'p' THEN 'partitioned_table'
+    WHEN 'f' THEN 'foreign_table'
+  END AS partition_type,
+  c.reltuples::bigint AS estimated_rows,
+  pg_catalog.pg_size_pretty(pg_catalog.pg_total_relation_size(c.oid)) AS total_size
+FROM
+  partitions p
+JOIN pg_catalog.pg_class c ON c.oid = p.oid
+JOIN pg_catalog.pg_class pc ON pc.oid = p.parent
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+ORDER BY
+  p.level,
+  pc.relname,
+  c.relname
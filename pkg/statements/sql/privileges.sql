@@ -0,0 +1,47 @@
+WITH RECURSIVE grants AS (
+  %s
+),
+memberships AS (
+  SELECT
+    r.rolname::text AS member,
+    r.oid AS roleid,
+    r.rolname::text AS role_name,
+    r.rolinherit AS inherit
+  FROM
+    pg_roles r
+  UNION
+  SELECT
+    ms.member,
+    b.oid,
+    b.rolname::text,
+    b.rolinherit
+  FROM
+    memberships ms
+  JOIN pg_auth_members m ON m.member = ms.roleid
+  JOIN pg_roles b ON b.oid = m.roleid
+  WHERE
+    ms.inherit
+)
+SELECT
+  ms.member AS role_name,
+  g.privilege_type,
+  g.is_grantable,
+  g.grantor,
+  CASE WHEN g.grantee = ms.member THEN 'direct' ELSE g.grantee END AS granted_via
+FROM
+  memberships ms
+JOIN grants g ON g.grantee = ms.role_name
+UNION ALL
+SELECT
+  r.rolname::text,
+  g.privilege_type,
+  g.is_grantable,
+  g.grantor,
+  'PUBLIC'
+FROM
+  pg_roles r
+JOIN grants g ON g.grantee = 'PUBLIC'
+ORDER BY
+  role_name,
+  privilege_type,
+  granted_via
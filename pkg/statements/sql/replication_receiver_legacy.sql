@@ -0,0 +1,11 @@
+SELECT
+  pg_last_xlog_receive_location()::text AS receive_lsn,
+  pg_last_xlog_replay_location()::text AS replay_lsn,
+  pg_xlog_location_diff(pg_last_xlog_receive_location(), pg_last_xlog_replay_location())::bigint AS replay_lag_bytes,
+  pg_size_pretty(pg_xlog_location_diff(pg_last_xlog_receive_location(), pg_last_xlog_replay_location())) AS replay_lag_size,
+  pg_last_xact_replay_timestamp() AS last_replay_time,
+  CASE
+    WHEN pg_last_xlog_receive_location() = pg_last_xlog_replay_location() THEN 0
+    ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+  END::float8 AS replay_lag_seconds,
+  NULL::text AS receiver_status
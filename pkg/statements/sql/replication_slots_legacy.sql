@@ -0,0 +1,15 @@
+SELECT
+  slot_name,
+  plugin,
+  slot_type,
+  database,
+  active,
+  restart_lsn::text AS restart_lsn,
+  NULL::text AS confirmed_flush_lsn,
+  pg_xlog_location_diff(pg_current_xlog_location(), restart_lsn)::bigint AS retained_wal_bytes,
+  pg_size_pretty(pg_xlog_location_diff(pg_current_xlog_location(), restart_lsn)) AS retained_wal
+FROM
+  pg_replication_slots
+ORDER BY
+  retained_wal_bytes DESC NULLS LAST,
+  slot_name
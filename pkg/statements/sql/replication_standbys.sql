@@ -0,0 +1,22 @@
+SELECT
+  pid,
+  usename AS username,
+  application_name,
+  client_addr,
+  state,
+  sync_state,
+  sent_lsn::text AS sent_lsn,
+  write_lsn::text AS write_lsn,
+  flush_lsn::text AS flush_lsn,
+  replay_lsn::text AS replay_lsn,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), sent_lsn)::bigint AS sent_lag_bytes,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), replay_lsn)::bigint AS replay_lag_bytes,
+  pg_size_pretty(pg_wal_lsn_diff(pg_current_wal_lsn(), replay_lsn)) AS replay_lag_size,
+  EXTRACT(EPOCH FROM write_lag)::float8 AS write_lag_seconds,
+  EXTRACT(EPOCH FROM flush_lag)::float8 AS flush_lag_seconds,
+  EXTRACT(EPOCH FROM replay_lag)::float8 AS replay_lag_seconds
+FROM
+  pg_stat_replication
+ORDER BY
+  application_name,
+  pid
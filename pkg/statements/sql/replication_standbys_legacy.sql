@@ -0,0 +1,22 @@
+SELECT
+  pid,
+  usename AS username,
+  application_name,
+  client_addr,
+  state,
+  sync_state,
+  sent_location::text AS sent_lsn,
+  write_location::text AS write_lsn,
+  flush_location::text AS flush_lsn,
+  replay_location::text AS replay_lsn,
+  pg_xlog_location_diff(pg_current_xlog_location(), sent_location)::bigint AS sent_lag_bytes,
+  pg_xlog_location_diff(pg_current_xlog_location(), replay_location)::bigint AS replay_lag_bytes,
+  pg_size_pretty(pg_xlog_location_diff(pg_current_xlog_location(), replay_location)) AS replay_lag_size,
+  NULL::float8 AS write_lag_seconds,
+  NULL::float8 AS flush_lag_seconds,
+  NULL::float8 AS replay_lag_seconds
+FROM
+  pg_stat_replication
+ORDER BY
+  application_name,
+  pid
@@ -0,0 +1,38 @@
+WITH RECURSIVE memberships AS (
+  SELECT
+    m.member,
+    m.roleid
+  FROM
+    pg_auth_members m
+  UNION
+  SELECT
+    ms.member,
+    m.roleid
+  FROM
+    memberships ms
+  JOIN pg_auth_members m ON m.member = ms.roleid
+)
+SELECT
+  r.rolname::text AS role_name,
+  r.rolsuper AS superuser,
+  r.rolinherit AS inherit,
+  r.rolcreaterole AS create_role,
+  r.rolcreatedb AS create_db,
+  r.rolcanlogin AS can_login,
+  r.rolreplication AS replication,
+  r.rolbypassrls AS bypass_rls,
+  r.rolconnlimit AS connection_limit,
+  r.rolvaliduntil AS valid_until,
+  array_to_string(ARRAY(
+    SELECT b.rolname FROM pg_auth_members m JOIN pg_roles b ON b.oid = m.roleid WHERE m.member = r.oid ORDER BY 1
+  ), ', ') AS member_of,
+  array_to_string(ARRAY(
+    SELECT b.rolname FROM memberships ms JOIN pg_roles b ON b.oid = ms.roleid WHERE ms.member = r.oid ORDER BY 1
+  ), ', ') AS all_member_of,
+  array_to_string(ARRAY(
+    SELECT b.rolname FROM pg_auth_members m JOIN pg_roles b ON b.oid = m.member WHERE m.roleid = r.oid ORDER BY 1
+  ), ', ') AS members
+FROM
+  pg_roles r
+ORDER BY
+  r.rolname
@@ -0,0 +1,112 @@
+WITH objects AS (
+  SELECT
+    'schema' AS type,
+    n.nspname AS schema_name,
+    NULL::name AS parent_name,
+    n.nspname AS name,
+    pg_catalog.obj_description(n.oid, 'pg_namespace') AS comment
+  FROM
+    pg_catalog.pg_namespace n
+
+  UNION ALL
+
+  SELECT
+    CASE c.relkind
+      WHEN 'r' THEN 'table'
+      WHEN 'p' THEN 'partitioned_table'
+      WHEN 'v' THEN 'view'
+      WHEN 'm' THEN 'materialized_view'
+      WHEN 'f' THEN 'foreign_table'
+      WHEN 'S' THEN 'sequence'
+      ELSE 'index'
+    END,
+    n.nspname,
+    CASE WHEN c.relkind IN ('i', 'I') THEN t.relname END,
+    c.relname,
+    pg_catalog.obj_description(c.oid, 'pg_class')
+  FROM
+    pg_catalog.pg_class c
+  JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+  LEFT JOIN pg_catalog.pg_index i ON i.indexrelid = c.oid
+  LEFT JOIN pg_catalog.pg_class t ON t.oid = i.indrelid
+  WHERE
+    c.relkind IN ('r', 'p', 'v', 'm', 'f', 'S', 'i', 'I')
+
+  UNION ALL
+
+  SELECT
+    'column',
+    n.nspname,
+    c.relname,
+    a.attname,
+    pg_catalog.col_description(c.oid, a.attnum)
+  FROM
+    pg_catalog.pg_attribute a
+  JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+  JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+  WHERE
+    c.relkind IN ('r', 'p', 'v', 'm', 'f')
+    AND a.attnum > 0
+    AND NOT a.attisdropped
+
+  UNION ALL
+
+  SELECT
+    'function',
+    n.nspname,
+    NULL,
+    p.proname,
+    pg_catalog.obj_description(p.oid, 'pg_proc')
+  FROM
+    pg_catalog.pg_proc p
+  JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+
+  UNION ALL
+
+  SELECT
+    'constraint',
+    n.nspname,
+    c.relname,
+    con.conname,
+    pg_catalog.obj_description(con.oid, 'pg_constraint')
+  FROM
+    pg_catalog.pg_constraint con
+  JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+  JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+),
+matches AS (
+  SELECT
+    o.*,
+    CASE
+      WHEN lower(o.name) = lower($1) THEN 1
+      WHEN o.name ILIKE $2 || '%' THEN 2
+      WHEN o.name ILIKE '%' || $2 || '%' THEN 3
+      ELSE 4
+    END AS rank
+  FROM
+    objects o
+  WHERE
+    o.schema_name !~ '^pg_(toast|temp)'
+    AND o.schema_name NOT IN ('information_schema', 'pg_catalog')
+    AND has_schema_privilege(o.schema_name, 'USAGE')
+    AND (o.name ILIKE '%' || $2 || '%' OR o.comment ILIKE '%' || $2 || '%')
+    AND ($3::text[] IS NULL OR o.type = ANY($3::text[]))
+)
+SELECT
+  type,
+  schema_name::text AS schema_name,
+  parent_name::text AS parent_name,
+  name::text AS name,
+  comment,
+  CASE WHEN rank = 4 THEN 'comment' ELSE 'name' END AS matched,
+  rank
+FROM
+  matches
+ORDER BY
+  rank,
+  length(name),
+  type,
+  schema_name,
+  parent_name NULLS FIRST,
+  name
+LIMIT $4
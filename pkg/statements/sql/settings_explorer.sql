@@ -0,0 +1,19 @@
+SELECT
+  name,
+  setting,
+  unit,
+  category,
+  short_desc,
+  context,
+  vartype,
+  source,
+  boot_val,
+  reset_val,
+  sourcefile,
+  sourceline,
+  pending_restart
+FROM
+  pg_catalog.pg_settings
+ORDER BY
+  category,
+  name
@@ -0,0 +1,14 @@
+SELECT
+  tables.schemaname AS schema_name,
+  tables.relname AS table_name,
+  pg_table_size(tables.relid) AS table_bytes,
+  pg_indexes_size(tables.relid) AS index_bytes,
+  pg_total_relation_size(tables.relid) AS total_bytes,
+  GREATEST(pg_class.reltuples, 0)::bigint AS estimated_rows
+FROM
+  pg_catalog.pg_statio_user_tables AS tables
+JOIN pg_class
+  ON pg_class.oid = tables.relid
+ORDER BY
+  tables.schemaname,
+  tables.relname
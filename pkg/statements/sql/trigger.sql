@@ -0,0 +1,20 @@
+SELECT
+  t.oid,
+  t.tgname AS trigger_name,
+  n.nspname AS schema_name,
+  c.relname AS table_name,
+  t.tgfoid::regprocedure::text AS function_name,
+  CASE t.tgenabled
+    WHEN 'O' THEN 'origin'
+    WHEN 'D' THEN 'disabled'
+    WHEN 'R' THEN 'replica'
+    WHEN 'A' THEN 'always'
+  END AS enabled,
+  pg_catalog.pg_get_triggerdef(t.oid, true) AS definition,
+  pg_catalog.obj_description(t.oid, 'pg_trigger') AS comment
+FROM
+  pg_catalog.pg_trigger t
+JOIN pg_catalog.pg_class c ON c.oid = t.tgrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE
+  t.oid = $1::oid
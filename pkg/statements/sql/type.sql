@@ -0,0 +1,41 @@
+SELECT
+  t.oid,
+  n.nspname AS schema_name,
+  t.typname AS type_name,
+  CASE t.typtype
+    WHEN 'c' THEN 'composite'
+    WHEN 'd' THEN 'domain'
+    WHEN 'e' THEN 'enum'
+    WHEN 'r' THEN 'range'
+  END AS type_kind,
+  pg_catalog.pg_get_userbyid(t.typowner) AS owner,
+  CASE t.typtype
+    WHEN 'e' THEN (
+      SELECT string_agg(quote_literal(e.enumlabel), ', ' ORDER BY e.enumsortorder)
+      FROM pg_catalog.pg_enum e
+      WHERE e.enumtypid = t.oid
+    )
+    WHEN 'd' THEN pg_catalog.format_type(t.typbasetype, t.typtypmod) || COALESCE((
+      SELECT ' ' || string_agg(pg_catalog.pg_get_constraintdef(con.oid, true), ' ' ORDER BY con.conname)
+      FROM pg_catalog.pg_constraint con
+      WHERE con.contypid = t.oid
+    ), '')
+    WHEN 'c' THEN (
+      SELECT string_agg(quote_ident(a.attname) || ' ' || pg_catalog.format_type(a.atttypid, a.atttypmod), ', ' ORDER BY a.attnum)
+      FROM pg_catalog.pg_attribute a
+      WHERE a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped
+    )
+    WHEN 'r' THEN (
+      SELECT pg_catalog.format_type(r.rngsubtype, NULL)
+      FROM pg_catalog.pg_range r
+      WHERE r.rngtypid = t.oid
+    )
+  END AS definition,
+  t.typnotnull AS not_null,
+  t.typdefault AS default_value,
+  pg_catalog.obj_description(t.oid, 'pg_type') AS comment
+FROM
+  pg_catalog.pg_type t
+JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+WHERE
+  t.oid = $1::oid
@@ -0,0 +1,31 @@
+SELECT
+  n.nspname AS schema_name,
+  c.relname AS table_name,
+  a.attname AS column_name,
+  COALESCE(bt.typname, t.typname) AS type_name,
+  COALESCE(bt.typcategory, t.typcategory) AS type_category,
+  EXISTS (
+    SELECT 1
+    FROM pg_catalog.pg_index i
+    WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+  ) AS primary_key
+FROM
+  pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_catalog.pg_type t ON t.oid = a.atttypid
+LEFT JOIN pg_catalog.pg_type bt ON t.typtype = 'd' AND bt.oid = t.typbasetype
+WHERE
+  -- Partitioned tables are searched through their partitions
+  c.relkind IN ('r', 'm')
+  AND a.attnum > 0
+  AND NOT a.attisdropped
+  AND n.nspname !~ '^pg_(toast|temp)'
+  AND n.nspname NOT IN ('information_schema', 'pg_catalog')
+  AND ($1::text[] IS NULL OR n.nspname = ANY($1::text[]))
+  AND has_schema_privilege(n.nspname, 'USAGE')
+  AND has_table_privilege(c.oid, 'SELECT')
+ORDER BY
+  n.nspname,
+  c.relname,
+  a.attnum
@@ -148,6 +148,8 @@ function buildSchemaSection(name, objects) {
 
   var titles = {
     "table":             "Tables",
+    "partitioned_table": "Partitioned Tables",
+    "foreign_table":     "Foreign Tables",
     "view":              "Views",
     "materialized_view": "Materialized Views",
     "function":          "Functions",
@@ -156,6 +158,8 @@ function buildSchemaSection(name, objects) {
 
   var icons = {
     "table":             '<i class="fa fa-table"></i>',
+    "partitioned_table": '<i class="fa fa-table"></i>',
+    "foreign_table":     '<i class="fa fa-table"></i>',
     "view":              '<i class="fa fa-table"></i>',
     "materialized_view": '<i class="fa fa-table"></i>',
     "function":          '<i class="fa fa-bolt"></i>',
@@ -169,7 +173,20 @@ function buildSchemaSection(name, objects) {
   section += "<div class='schema-name'><i class='fa fa-folder-o'></i><i class='fa fa-folder-open-o'></i> " + name + "</div>";
   section += "<div class='schema-container'>";
 
-  ["table", "view", "materialized_view", "function", "sequence"].forEach(function(group) {
+  // Partitions are nested under their partitioned table and could live in another schema
+  var buildPartitions = function(partitions, depth) {
+    var items = "";
+
+    (partitions || []).forEach(function(item) {
+      var type = item.partitions ? "partitioned_table" : "table";
+      items += "<li class='schema-item schema-partition schema-" + type + "' style='padding-left: " + (16 + depth * 16) + "px' data-type='" + type + "' data-id='" + item.schema + "." + item.name + "' data-name='" + item.name + "'>" + icons[type] + "&nbsp;" + item.name + "</li>";
+      items += buildPartitions(item.partitions, depth + 1);
+    });
+
+    return items;
+  };
+
+  ["table", "partitioned_table", "foreign_table", "view", "materialized_view", "function", "sequence"].forEach(function(group) {
     group_klass = "";
     if (name == "public" && group == "table") group_klass = "expanded";
 
@@ -187,6 +204,10 @@ function buildSchemaSection(name, objects) {
         }
 
         section += "<li class='schema-item schema-" + group + "' data-type='" + group + "' data-id='" + id + "' data-name='" + item.name + "'>" + icons[group] + "&nbsp;" + item.name + "</li>";
+
+        if (group == "partitioned_table") {
+          section += buildPartitions(item.partitions, 1);
+        }
       });
       section += "</ul></div>";
     }
@@ -231,6 +252,8 @@ function loadSchemas() {
   var emptyObjectList = function() {
     return {
       table: [],
+      partitioned_table: [],
+      foreign_table: [],
       view: [],
       materialized_view: [],
       function: [],
@@ -271,7 +294,7 @@ function loadSchemas() {
       autocompleteObjects = [];
       for (schema in data) {
         for (kind in data[schema]) {
-          if (!(kind == "table" || kind == "view" || kind == "materialized_view" || kind == "function")) {
+          if (!(kind == "table" || kind == "partitioned_table" || kind == "foreign_table" || kind == "view" || kind == "materialized_view" || kind == "function")) {
             continue
           }
 